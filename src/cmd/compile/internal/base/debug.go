@@ -17,6 +17,7 @@ var Debug DebugFlags
 // Each setting is name=value; for ints, name is short for name=1.
 type DebugFlags struct {
 	AlignHot              int    `help:"enable hot block alignment (currently requires -pgo)" concurrent:"ok"`
+	AlignHotBytes         int    `help:"always align hot loop headers to this many bytes on amd64, 386 and arm64, instead of the architecture default (implies -d=alignhot)" concurrent:"ok"`
 	Append                int    `help:"print information about append compilation"`
 	Checkptr              int    `help:"instrument unsafe pointer conversions\n0: instrumentation disabled\n1: conversions involving unsafe.Pointer are instrumented\n2: conversions to unsafe.Pointer force heap allocation" concurrent:"ok"`
 	Closure               int    `help:"print information about closure compilation"`
@@ -50,6 +51,7 @@ type DebugFlags struct {
 	Nil                   int    `help:"print information about nil checks"`
 	NoOpenDefer           int    `help:"disable open-coded defers" concurrent:"ok"`
 	NoRefName             int    `help:"do not include referenced symbol names in object file" concurrent:"ok"`
+	PadJumpsHotOnly       int    `help:"on amd64, only pad jumps against the Intel JCC erratum in profile-hot functions (without a profile, all functions are padded)" concurrent:"ok"`
 	PCTab                 string `help:"print named pc-value table\nOne of: pctospadj, pctofile, pctoline, pctoinline, pctopcdata"`
 	Panic                 int    `help:"show all compiler panics"`
 	Reshape               int    `help:"print information about expression reshaping"`
@@ -67,7 +69,19 @@ type DebugFlags struct {
 	PGOInline             int    `help:"enable profile-guided inlining" concurrent:"ok"`
 	PGOInlineCDFThreshold string `help:"cumulative threshold percentage for determining call sites as hot candidates for inlining" concurrent:"ok"`
 	PGOInlineBudget       int    `help:"inline budget for hot functions" concurrent:"ok"`
+	PGOInlineColdThresh   string `help:"edge weight percentage at or below which call sites are considered cold and only very cheap callees are inlined there; empty to disable" concurrent:"ok"`
+	PGOInlineScale        int    `help:"scale the inline budget of hot call sites with their edge weight, from the -d=pgoinlinebudget budget for the hottest down to the regular budget at the -d=pgoinlinecdfthreshold threshold" concurrent:"ok"`
 	PGODevirtualize       int    `help:"enable profile-guided devirtualization; 0 to disable, 1 to enable interface devirtualization, 2 to enable function devirtualization" concurrent:"ok"`
+	PGODevirtNoInline     int    `help:"profile-guided devirtualize hot calls even if the callee cannot be inlined, for a direct call fast path" concurrent:"ok"`
+	PGOTextLayout         int    `help:"mark profile-hot and never-sampled functions for the linker to group at the start and end of the text with -hotcoldtext" concurrent:"ok"`
+	PGOTextCDFThreshold   string `help:"cumulative threshold percentage of function entry weight for determining functions placed in hot text" concurrent:"ok"`
+	PGODwarf              int    `help:"record the profile-guided text placement of functions in DWARF, as DW_AT_go_hotness" concurrent:"ok"`
+	PGOFuncAlign          int    `help:"align profile-hot functions to this many bytes (0 to disable)" concurrent:"ok"`
+	PGODecisions          string `help:"read the hot/cold decisions of a previous build from this file, to apply hysteresis" concurrent:"ok"`
+	PGODecisionsOut       string `help:"write the hot/cold decisions of this build to this file (not written on build cache hits; use go build -a)" concurrent:"ok"`
+	PGOMatchFloor         int    `help:"fail if less than this percentage of the profile weight of the package's functions is attributed to functions found in the package" concurrent:"ok"`
+	PGOReport             string `help:"write a JSON report of the profile-guided optimization decisions of the package to this file, or to a file named after the package in this directory" concurrent:"ok"`
+	PGOHysteresis         int    `help:"percentage by which a weight must cross the hot threshold to flip a previous hot/cold decision" concurrent:"ok"`
 	RangeFuncCheck        int    `help:"insert code to check behavior of range iterator functions" concurrent:"ok"`
 	WrapGlobalMapDbg      int    `help:"debug trace output for global map init wrapping"`
 	WrapGlobalMapCtl      int    `help:"global map init wrap control (0 => default, 1 => off, 2 => stress mode, no size cutoff)"`
@@ -126,6 +126,7 @@ type CmdFlags struct {
 	TrimPath           string       "help:\"remove `prefix` from recorded source file paths\""
 	WB                 bool         "help:\"enable write barrier\"" // TODO: remove
 	PgoProfile         string       "help:\"read profile or pre-process profile from `file`\""
+	PgoColdSize        bool         "help:\"only inline the cheapest callees into functions that do not appear in the profile\""
 	ErrorURL           bool         "help:\"print explanatory URL with error message if applicable\""
 
 	// Configuration derived from flags; not a flag itself.
@@ -183,6 +184,7 @@ func ParseFlags() {
 	Debug.InlStaticInit = 1
 	Debug.PGOInline = 1
 	Debug.PGODevirtualize = 2
+	Debug.PGOHysteresis = 20
 	Debug.SyncFrames = -1 // disable sync markers by default
 	Debug.ZeroCopy = 1
 	Debug.RangeFuncCheck = 1
@@ -340,6 +342,15 @@ func ParseFlags() {
 	if Flag.LowerC < 1 {
 		log.Fatalf("-c must be at least 1, got %d", Flag.LowerC)
 	}
+	if a := Debug.AlignHotBytes; a != 0 && (a&(a-1) != 0 || a < 8 || a > 2048) {
+		log.Fatalf("-d=alignhotbytes must be a power of two between 8 and 2048, got %d", a)
+	}
+	if f := Debug.PGOMatchFloor; f < 0 || f > 100 {
+		log.Fatalf("-d=pgomatchfloor must be between 0 and 100, got %d", f)
+	}
+	if a := Debug.PGOFuncAlign; a < 0 || a&(a-1) != 0 {
+		log.Fatalf("-d=pgofuncalign must be a power of two, got %d", a)
+	}
 	if !concurrentBackendAllowed() {
 		Flag.LowerC = 1
 	}
@@ -363,6 +374,14 @@ func ParseFlags() {
 
 	// set via a -d flag
 	Ctxt.Debugpcln = Debug.PCTab
+	// Without a profile no function is hot, so keep padding everything.
+	Ctxt.PadJumpsHotOnly = Debug.PadJumpsHotOnly != 0 && Flag.PgoProfile != ""
+
+	// -d=alignhotbytes asks for hot block alignment, even if -d=alignhot
+	// was turned off.
+	if Debug.AlignHotBytes != 0 {
+		Debug.AlignHot = 1
+	}
 
 	// https://golang.org/issue/67502
 	if buildcfg.GOOS == "plan9" && buildcfg.GOARCH == "386" {
@@ -170,6 +170,8 @@ func ProfileGuided(fn *ir.Func, p *pgoir.Profile) {
 			return n
 		}
 
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, true, "")
+
 		if stat != nil {
 			stat.Devirtualized = ir.LinkFuncName(callee)
 			stat.DevirtualizedWeight = weight
@@ -197,14 +199,17 @@ func maybeDevirtualizeInterfaceCall(p *pgoir.Profile, fn *ir.Func, call *ir.Call
 	// Bail if we do not have a Type node for the hot callee.
 	ctyp := methodRecvType(callee)
 	if ctyp == nil {
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, false, "no receiver type")
 		return nil, nil, 0
 	}
 	// Bail if we know for sure it won't inline.
 	if !shouldPGODevirt(callee) {
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, false, "callee cannot be inlined")
 		return nil, nil, 0
 	}
 	// Bail if de-selected by PGO Hash.
 	if !base.PGOHash.MatchPosWithInfo(call.Pos(), "devirt", nil) {
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, false, "de-selected by pgohash")
 		return nil, nil, 0
 	}
 
@@ -238,6 +243,7 @@ func maybeDevirtualizeFunctionCall(p *pgoir.Profile, fn *ir.Func, call *ir.CallE
 		if base.Debug.PGODebug >= 3 {
 			fmt.Printf("callee %s is a closure, skipping\n", ir.FuncName(callee))
 		}
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, false, "callee is a closure")
 		return nil, nil, 0
 	}
 	// runtime.memhash_varlen does not look like a closure, but it uses
@@ -247,6 +253,7 @@ func maybeDevirtualizeFunctionCall(p *pgoir.Profile, fn *ir.Func, call *ir.CallE
 		if base.Debug.PGODebug >= 3 {
 			fmt.Printf("callee %s is a closure (runtime.memhash_varlen), skipping\n", ir.FuncName(callee))
 		}
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, false, "callee is a closure")
 		return nil, nil, 0
 	}
 	// TODO(prattmic): We don't properly handle methods as callees in two
@@ -280,21 +287,38 @@ func maybeDevirtualizeFunctionCall(p *pgoir.Profile, fn *ir.Func, call *ir.CallE
 		if base.Debug.PGODebug >= 3 {
 			fmt.Printf("callee %s is a method, skipping\n", ir.FuncName(callee))
 		}
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, false, "callee is a method")
 		return nil, nil, 0
 	}
 
 	// Bail if we know for sure it won't inline.
 	if !shouldPGODevirt(callee) {
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, false, "callee cannot be inlined")
 		return nil, nil, 0
 	}
 	// Bail if de-selected by PGO Hash.
 	if !base.PGOHash.MatchPosWithInfo(call.Pos(), "devirt", nil) {
+		reportDevirt(fn, call, ir.LinkFuncName(callee), weight, false, "de-selected by pgohash")
 		return nil, nil, 0
 	}
 
 	return rewriteFunctionCall(call, fn, callee), callee, weight
 }
 
+// reportDevirt records a decision on devirtualizing call to the hot callee,
+// named by its linker symbol name, in the -d=pgoreport report.
+func reportDevirt(fn *ir.Func, call *ir.CallExpr, callee string, weight int64, applied bool, reason string) {
+	pgoir.Report(pgoir.Decision{
+		Kind:    pgoir.DecisionDevirtualize,
+		Pos:     ir.Line(call),
+		Func:    ir.LinkFuncName(fn),
+		Callee:  callee,
+		Weight:  weight,
+		Applied: applied,
+		Reason:  reason,
+	})
+}
+
 // shouldPGODevirt checks if we should perform PGO devirtualization to the
 // target function.
 //
@@ -315,6 +339,14 @@ func shouldPGODevirt(fn *ir.Func) bool {
 		}()
 	}
 
+	// With -d=pgodevirtnoinline, keep the type check and direct call
+	// even if the callee won't inline. A direct call is predicted
+	// statically and skips loading the target from the itab or closure,
+	// which is still a win on a hot call site with one dominant callee.
+	if base.Debug.PGODevirtNoInline != 0 {
+		return true
+	}
+
 	reason = inline.InlineImpossible(fn)
 	if reason != "" {
 		return false
@@ -649,15 +681,19 @@ func interfaceCallRecvTypeAndMethod(call *ir.CallExpr) (*types.Type, *types.Sym)
 
 // findHotConcreteCallee returns the *ir.Func of the hottest callee of a call,
 // if available, and its edge weight. extraFn can perform additional
-// applicability checks on each candidate edge. If extraFn returns false,
-// candidate will not be considered a valid callee candidate.
-func findHotConcreteCallee(p *pgoir.Profile, caller *ir.Func, call *ir.CallExpr, extraFn func(callerName string, callOffset int, candidate *pgoir.IREdge) bool) (*ir.Func, int64) {
+// applicability checks on each candidate edge. If extraFn returns a non-empty
+// reason, candidate will not be considered a valid callee candidate.
+func findHotConcreteCallee(p *pgoir.Profile, caller *ir.Func, call *ir.CallExpr, extraFn func(callerName string, callOffset int, candidate *pgoir.IREdge) (reason string)) (*ir.Func, int64) {
 	callerName := ir.LinkFuncName(caller)
 	callerNode := p.WeightedCG.IRNodes[callerName]
 	callOffset := pgoir.NodeLineOffset(call, caller)
 
 	var hottest *pgoir.IREdge
 
+	// The hottest candidate rejected by extraFn, and why.
+	var rejected *pgoir.IREdge
+	var rejectedReason string
+
 	// Returns true if e is hotter than hottest.
 	//
 	// Naively this is just e.Weight > hottest.Weight, but because OutEdges
@@ -722,8 +758,13 @@ func findHotConcreteCallee(p *pgoir.Profile, caller *ir.Func, call *ir.CallExpr,
 			continue
 		}
 
-		if extraFn != nil && !extraFn(callerName, callOffset, e) {
-			continue
+		if extraFn != nil {
+			if reason := extraFn(callerName, callOffset, e); reason != "" {
+				if e.Weight > 0 && (rejected == nil || e.Weight > rejected.Weight) {
+					rejected, rejectedReason = e, reason
+				}
+				continue
+			}
 		}
 
 		if base.Debug.PGODebug >= 2 {
@@ -732,6 +773,20 @@ func findHotConcreteCallee(p *pgoir.Profile, caller *ir.Func, call *ir.CallExpr,
 		hottest = e
 	}
 
+	// Report why the call won't be devirtualized to its hottest callee.
+	// Candidates rejected by extraFn are often callees of a different
+	// call on the same line, so only report them if there is nothing
+	// else.
+	switch {
+	case hottest == nil && rejected != nil:
+		reportDevirt(caller, call, rejected.Dst.Name(), rejected.Weight, false, rejectedReason)
+	case hottest == nil || hottest.Dst.AST != nil:
+	case strings.Contains(hottest.Dst.Name(), "["):
+		reportDevirt(caller, call, hottest.Dst.Name(), hottest.Weight, false, "callee is an instantiated generic function")
+	default:
+		reportDevirt(caller, call, hottest.Dst.Name(), hottest.Weight, false, "callee IR not available")
+	}
+
 	if hottest == nil {
 		if base.Debug.PGODebug >= 2 {
 			fmt.Printf("%v: call %s:%d: no hot callee\n", ir.Line(call), callerName, callOffset)
@@ -750,7 +805,7 @@ func findHotConcreteCallee(p *pgoir.Profile, caller *ir.Func, call *ir.CallExpr,
 func findHotConcreteInterfaceCallee(p *pgoir.Profile, caller *ir.Func, call *ir.CallExpr) (*ir.Func, int64) {
 	inter, method := interfaceCallRecvTypeAndMethod(call)
 
-	return findHotConcreteCallee(p, caller, call, func(callerName string, callOffset int, e *pgoir.IREdge) bool {
+	return findHotConcreteCallee(p, caller, call, func(callerName string, callOffset int, e *pgoir.IREdge) string {
 		ctyp := methodRecvType(e.Dst.AST)
 		if ctyp == nil {
 			// Not a method.
@@ -758,7 +813,7 @@ func findHotConcreteInterfaceCallee(p *pgoir.Profile, caller *ir.Func, call *ir.
 			if base.Debug.PGODebug >= 2 {
 				fmt.Printf("%v: edge %s:%d -> %s (weight %d): callee not a method\n", ir.Line(call), callerName, callOffset, e.Dst.Name(), e.Weight)
 			}
-			return false
+			return "callee not a method"
 		}
 
 		// If ctyp doesn't implement inter it is most likely from a
@@ -777,7 +832,7 @@ func findHotConcreteInterfaceCallee(p *pgoir.Profile, caller *ir.Func, call *ir.
 				why := typecheck.ImplementsExplain(ctyp, inter)
 				fmt.Printf("%v: edge %s:%d -> %s (weight %d): %v doesn't implement %v (%s)\n", ir.Line(call), callerName, callOffset, e.Dst.Name(), e.Weight, ctyp, inter, why)
 			}
-			return false
+			return fmt.Sprintf("receiver %v doesn't implement %v", ctyp, inter)
 		}
 
 		// If the method name is different it is most likely from a
@@ -786,10 +841,10 @@ func findHotConcreteInterfaceCallee(p *pgoir.Profile, caller *ir.Func, call *ir.
 			if base.Debug.PGODebug >= 2 {
 				fmt.Printf("%v: edge %s:%d -> %s (weight %d): callee is a different method\n", ir.Line(call), callerName, callOffset, e.Dst.Name(), e.Weight)
 			}
-			return false
+			return "callee is a different method"
 		}
 
-		return true
+		return ""
 	})
 }
 
@@ -798,7 +853,7 @@ func findHotConcreteInterfaceCallee(p *pgoir.Profile, caller *ir.Func, call *ir.
 func findHotConcreteFunctionCallee(p *pgoir.Profile, caller *ir.Func, call *ir.CallExpr) (*ir.Func, int64) {
 	typ := call.Fun.Type().Underlying()
 
-	return findHotConcreteCallee(p, caller, call, func(callerName string, callOffset int, e *pgoir.IREdge) bool {
+	return findHotConcreteCallee(p, caller, call, func(callerName string, callOffset int, e *pgoir.IREdge) string {
 		ctyp := e.Dst.AST.Type().Underlying()
 
 		// If ctyp doesn't match typ it is most likely from a different
@@ -812,9 +867,9 @@ func findHotConcreteFunctionCallee(p *pgoir.Profile, caller *ir.Func, call *ir.C
 			if base.Debug.PGODebug >= 2 {
 				fmt.Printf("%v: edge %s:%d -> %s (weight %d): %v doesn't match %v\n", ir.Line(call), callerName, callOffset, e.Dst.Name(), e.Weight, ctyp, typ)
 			}
-			return false
+			return fmt.Sprintf("callee type %v doesn't match %v", ctyp, typ)
 		}
 
-		return true
+		return ""
 	})
 }
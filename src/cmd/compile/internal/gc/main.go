@@ -140,6 +140,7 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 		base.Ctxt.DebugInfo = dwarfgen.Info
 		base.Ctxt.GenAbstractFunc = dwarfgen.AbstractFunc
 		base.Ctxt.DwFixups = obj.NewDwarfFixupTable(base.Ctxt)
+		base.Ctxt.Flag_dwarfhotness = base.Debug.PGODwarf != 0
 	} else {
 		// turn off inline generation if no dwarf at all
 		base.Flag.GenDwarfInl = 0
@@ -230,6 +231,9 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 	// Interleaved devirtualization and inlining.
 	base.Timer.Start("fe", "devirtualize-and-inline")
 	interleaved.DevirtualizeAndInlinePackage(typecheck.Target, profile)
+	if err := profile.WriteDecisions(); err != nil {
+		log.Fatalf("%s: PGO error: %v", base.Flag.PgoProfile, err)
+	}
 
 	noder.MakeWrappers(typecheck.Target) // must happen after inlining
 
@@ -349,6 +353,10 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 	ssagen.CheckLargeStacks()
 	typecheck.CheckFuncStack()
 
+	if err := pgoir.WriteReport(); err != nil {
+		log.Fatalf("error writing PGO report: %v", err)
+	}
+
 	if len(compilequeue) != 0 {
 		base.Fatalf("%d uncompiled functions", len(compilequeue))
 	}
@@ -54,6 +54,8 @@ const (
 
 	inlineBigFunctionNodes   = 5000 // Functions with this many nodes are considered "big".
 	inlineBigFunctionMaxCost = 20   // Max cost of inlinee when inlining into a "big" function.
+
+	inlineColdMaxCost = 20 // Max cost of inlinee at a profile-cold call site, with -d=pgoinlinecoldthresh or -pgocoldsize.
 )
 
 var (
@@ -79,6 +81,27 @@ var (
 
 	// Budget increased due to hotness.
 	inlineHotMaxBudget int32 = 2000
+
+	// Threshold in percentage of total edge weight at or below which
+	// call sites are considered cold, or negative if disabled. See
+	// -d=pgoinlinecoldthresh.
+	inlineColdCallSiteThresholdPercent = float64(-1)
+
+	// Set of call sites in the profile above the cold threshold. Only
+	// populated if the cold threshold is enabled.
+	warmEdgeMap = make(map[pgoir.CallSiteInfo]struct{})
+
+	// Set of functions that never appear in the profile, with -pgocoldsize.
+	// Only the cheapest callees are inlined into them.
+	coldSizeFuncs = make(map[*ir.Func]struct{})
+
+	// Per call site budgets of hot call sites, with -d=pgoinlinescale.
+	// Call sites not in the map use inlineHotMaxBudget.
+	candHotEdgeBudget = make(map[pgoir.CallSiteInfo]int32)
+
+	// Reasons that functions of this package cannot be inlined, recorded
+	// with -d=pgoreport to report the hot call sites they prevent.
+	cannotInlineReason = make(map[*ir.Func]string)
 )
 
 func IsPgoHotFunc(fn *ir.Func, profile *pgoir.Profile) bool {
@@ -106,8 +129,16 @@ func PGOInlinePrologue(p *pgoir.Profile) {
 			base.Fatalf("invalid PGOInlineCDFThreshold, must be between 0 and 100")
 		}
 	}
+	if base.Debug.PGOInlineColdThresh != "" {
+		if s, err := strconv.ParseFloat(base.Debug.PGOInlineColdThresh, 64); err == nil && s >= 0 && s <= 100 {
+			inlineColdCallSiteThresholdPercent = s
+		} else {
+			base.Fatalf("invalid PGOInlineColdThresh, must be between 0 and 100")
+		}
+	}
 	var hotCallsites []pgo.NamedCallEdge
 	inlineHotCallSiteThresholdPercent, hotCallsites = hotNodesFromCDF(p)
+	hotCallsites = p.HotEdges(hotCallsites)
 	if base.Debug.PGODebug > 0 {
 		fmt.Printf("hot-callsite-thres-from-CDF=%v\n", inlineHotCallSiteThresholdPercent)
 	}
@@ -116,6 +147,7 @@ func PGOInlinePrologue(p *pgoir.Profile) {
 		inlineHotMaxBudget = int32(x)
 	}
 
+	var cum int64
 	for _, n := range hotCallsites {
 		// mark inlineable callees from hot edges
 		if callee := p.WeightedCG.IRNodes[n.CalleeName]; callee != nil {
@@ -125,6 +157,31 @@ func PGOInlinePrologue(p *pgoir.Profile) {
 		if caller := p.WeightedCG.IRNodes[n.CallerName]; caller != nil && caller.AST != nil {
 			csi := pgoir.CallSiteInfo{LineOffset: n.CallSiteOffset, Caller: caller.AST}
 			candHotEdgeMap[csi] = struct{}{}
+			if base.Debug.PGOInlineScale != 0 {
+				if b := scaledHotBudget(cum, p.TotalWeight); b > candHotEdgeBudget[csi] {
+					candHotEdgeBudget[csi] = b
+				}
+			}
+		}
+		cum += p.NamedEdgeMap.Weight[n]
+	}
+
+	if inlineColdCallSiteThresholdPercent >= 0 {
+		for n, w := range p.NamedEdgeMap.Weight {
+			if pgo.WeightInPercentage(w, p.TotalWeight) <= inlineColdCallSiteThresholdPercent {
+				continue
+			}
+			if caller := p.WeightedCG.IRNodes[n.CallerName]; caller != nil && caller.AST != nil {
+				warmEdgeMap[pgoir.CallSiteInfo{LineOffset: n.CallSiteOffset, Caller: caller.AST}] = struct{}{}
+			}
+		}
+	}
+
+	if base.Flag.PgoColdSize {
+		for _, n := range p.WeightedCG.IRNodes {
+			if n.AST != nil && p.NeverSampled(n.AST) {
+				coldSizeFuncs[n.AST] = struct{}{}
+			}
 		}
 	}
 
@@ -134,6 +191,25 @@ func PGOInlinePrologue(p *pgoir.Profile) {
 	}
 }
 
+// scaledHotBudget returns the budget of a hot call site whose edge is
+// preceded by edges making up cum of the total weight. The budget scales
+// linearly with the position of the edge in the CDF, from
+// inlineHotMaxBudget for the hottest edge down to inlineMaxBudget at the
+// CDF threshold, so that edges just inside the threshold are not treated
+// much differently from edges just outside it. The upper end is set with
+// -d=pgoinlinebudget, and the CDF threshold with -d=pgoinlinecdfthreshold.
+func scaledHotBudget(cum, total int64) int32 {
+	if inlineCDFHotCallSiteThresholdPercent == 0 {
+		// Only the hottest edge is hot.
+		return inlineHotMaxBudget
+	}
+	frac := pgo.WeightInPercentage(cum, total) / inlineCDFHotCallSiteThresholdPercent
+	if frac > 1 {
+		frac = 1
+	}
+	return inlineHotMaxBudget - int32(float64(inlineHotMaxBudget-inlineMaxBudget)*frac)
+}
+
 // hotNodesFromCDF computes an edge weight threshold and the list of hot
 // nodes that make up the given percentage of the CDF. The threshold, as
 // a percent, is the lower bound of weight for nodes to be considered hot
@@ -268,7 +344,7 @@ func CanInline(fn *ir.Func, profile *pgoir.Profile) {
 	}
 
 	var reason string // reason, if any, that the function was not inlined
-	if base.Flag.LowerM > 1 || logopt.Enabled() {
+	if base.Flag.LowerM > 1 || logopt.Enabled() || base.Debug.PGOReport != "" {
 		defer func() {
 			if reason != "" {
 				if base.Flag.LowerM > 1 {
@@ -277,6 +353,9 @@ func CanInline(fn *ir.Func, profile *pgoir.Profile) {
 				if logopt.Enabled() {
 					logopt.LogOpt(fn.Pos(), "cannotInlineFunction", "inline", ir.FuncName(fn), reason)
 				}
+				if base.Debug.PGOReport != "" {
+					cannotInlineReason[fn] = reason
+				}
 			}
 		}()
 	}
@@ -834,12 +913,32 @@ func TryInlineCall(callerfn *ir.Func, call *ir.CallExpr, bigCaller bool, profile
 	if ir.IsIntrinsicCall(call) {
 		return nil
 	}
-	if fn := inlCallee(callerfn, call.Fun, profile); fn != nil && typecheck.HaveInlineBody(fn) {
-		return mkinlcall(callerfn, call, fn, bigCaller)
+	if fn := inlCallee(callerfn, call.Fun, profile); fn != nil {
+		if typecheck.HaveInlineBody(fn) {
+			return mkinlcall(callerfn, call, fn, bigCaller)
+		}
+		reportNotInlinable(callerfn, call, fn)
 	}
 	return nil
 }
 
+// reportNotInlinable records in the -d=pgoreport report that the hot call
+// site call cannot be inlined because its callee fn is not inlinable.
+func reportNotInlinable(callerfn *ir.Func, call *ir.CallExpr, fn *ir.Func) {
+	if base.Debug.PGOReport == "" {
+		return
+	}
+	csi := pgoir.CallSiteInfo{LineOffset: pgoir.NodeLineOffset(call, callerfn), Caller: callerfn}
+	if _, hot := candHotEdgeMap[csi]; !hot {
+		return
+	}
+	reason := "callee is not inlinable"
+	if r := cannotInlineReason[fn]; r != "" {
+		reason += ": " + r
+	}
+	reportInline(call, callerfn, fn, false, reason)
+}
+
 // inlCallee takes a function-typed expression and returns the underlying function ONAME
 // that it refers to if statically known. Otherwise, it returns nil.
 func inlCallee(caller *ir.Func, fn ir.Node, profile *pgoir.Profile) (res *ir.Func) {
@@ -912,6 +1011,23 @@ func inlineCostOK(n *ir.CallExpr, caller, callee *ir.Func, bigCaller bool) (bool
 	csi := pgoir.CallSiteInfo{LineOffset: lineOffset, Caller: caller}
 	_, hot := candHotEdgeMap[csi]
 
+	if !hot && maxCost > inlineColdMaxCost {
+		// Call sites that are absent from the profile, or too light in
+		// it, are unlikely to benefit from inlining, so only allow the
+		// cheapest callees, which don't grow the text much.
+		_, warm := warmEdgeMap[csi]
+		_, coldSize := coldSizeFuncs[caller]
+		if coldSize || (inlineColdCallSiteThresholdPercent >= 0 && !warm) {
+			if metric > inlineColdMaxCost && metric <= maxCost {
+				if base.Debug.PGODebug > 0 {
+					fmt.Printf("cold check disallows inlining for call %s (cost %d) at %v in function %s\n", ir.PkgFuncName(callee), callee.Inl.Cost, ir.Line(n), ir.PkgFuncName(caller))
+				}
+				reportInline(n, caller, callee, false, fmt.Sprintf("cost %d exceeds cold budget %d", metric, inlineColdMaxCost))
+			}
+			maxCost = inlineColdMaxCost
+		}
+	}
+
 	if metric <= maxCost {
 		// Simple case. Function is already cheap enough.
 		return true, 0, metric, hot
@@ -931,15 +1047,22 @@ func inlineCostOK(n *ir.CallExpr, caller, callee *ir.Func, bigCaller bool) (bool
 		if base.Debug.PGODebug > 0 {
 			fmt.Printf("hot-big check disallows inlining for call %s (cost %d) at %v in big function %s\n", ir.PkgFuncName(callee), callee.Inl.Cost, ir.Line(n), ir.PkgFuncName(caller))
 		}
+		reportInline(n, caller, callee, false, fmt.Sprintf("cost %d exceeds budget %d of big function", metric, maxCost))
 		return false, maxCost, metric, false
 	}
 
-	if metric > inlineHotMaxBudget {
-		return false, inlineHotMaxBudget, metric, false
+	hotBudget := inlineHotMaxBudget
+	if b, ok := candHotEdgeBudget[csi]; ok {
+		hotBudget = b
+	}
+	if metric > hotBudget {
+		reportInline(n, caller, callee, false, fmt.Sprintf("cost %d exceeds hot budget %d", metric, hotBudget))
+		return false, hotBudget, metric, false
 	}
 
 	if !base.PGOHash.MatchPosWithInfo(n.Pos(), "inline", nil) {
 		// De-selected by PGO Hash.
+		reportInline(n, caller, callee, false, "de-selected by pgohash")
 		return false, maxCost, metric, false
 	}
 
@@ -950,6 +1073,19 @@ func inlineCostOK(n *ir.CallExpr, caller, callee *ir.Func, bigCaller bool) (bool
 	return true, 0, metric, hot
 }
 
+// reportInline records a decision on a hot or cold call site in the
+// -d=pgoreport report.
+func reportInline(n *ir.CallExpr, caller, callee *ir.Func, applied bool, reason string) {
+	pgoir.Report(pgoir.Decision{
+		Kind:    pgoir.DecisionInline,
+		Pos:     ir.Line(n),
+		Func:    ir.LinkFuncName(caller),
+		Callee:  ir.LinkFuncName(callee),
+		Applied: applied,
+		Reason:  reason,
+	})
+}
+
 // canInlineCallExpr returns true if the call n from caller to callee
 // can be inlined, plus the score computed for the call expr in question,
 // and whether the callee is hot according to PGO.
@@ -1048,6 +1184,7 @@ func mkinlcall(callerfn *ir.Func, n *ir.CallExpr, fn *ir.Func, bigCaller bool) *
 	}
 	if hot {
 		hasHotCall[callerfn] = struct{}{}
+		reportInline(n, callerfn, fn, true, fmt.Sprintf("cost %d", score))
 	}
 	typecheck.AssertFixedCall(n)
 
@@ -0,0 +1,42 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inline
+
+import "testing"
+
+func TestScaledHotBudget(t *testing.T) {
+	const total = 1000
+	threshold := int64(inlineCDFHotCallSiteThresholdPercent * total / 100)
+
+	if got := scaledHotBudget(0, total); got != inlineHotMaxBudget {
+		t.Errorf("scaledHotBudget of the hottest edge = %d, want %d", got, inlineHotMaxBudget)
+	}
+	if got := scaledHotBudget(threshold, total); got != inlineMaxBudget {
+		t.Errorf("scaledHotBudget at the CDF threshold = %d, want %d", got, inlineMaxBudget)
+	}
+	if got := scaledHotBudget(total, total); got != inlineMaxBudget {
+		t.Errorf("scaledHotBudget past the CDF threshold = %d, want %d", got, inlineMaxBudget)
+	}
+
+	prev := scaledHotBudget(0, total)
+	for cum := int64(1); cum <= threshold; cum++ {
+		b := scaledHotBudget(cum, total)
+		if b > prev || b < inlineMaxBudget || b > inlineHotMaxBudget {
+			t.Fatalf("scaledHotBudget(%d, %d) = %d, want in [%d, %d] and at most %d", cum, total, b, inlineMaxBudget, inlineHotMaxBudget, prev)
+		}
+		prev = b
+	}
+}
+
+func TestScaledHotBudgetZeroThreshold(t *testing.T) {
+	defer func(old float64) { inlineCDFHotCallSiteThresholdPercent = old }(inlineCDFHotCallSiteThresholdPercent)
+	inlineCDFHotCallSiteThresholdPercent = 0
+
+	// With a threshold of 0, only the hottest edge is hot, and it keeps
+	// the full hot budget rather than dividing by zero.
+	if got := scaledHotBudget(0, 1000); got != inlineHotMaxBudget {
+		t.Errorf("scaledHotBudget with a CDF threshold of 0 = %d, want %d", got, inlineHotMaxBudget)
+	}
+}
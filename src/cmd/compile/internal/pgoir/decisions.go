@@ -0,0 +1,97 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgoir
+
+import (
+	"bufio"
+	"cmd/compile/internal/base"
+	"cmd/internal/pgo"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Hot/cold decisions and hysteresis.
+//
+// Whether a call edge is hot for inlining, or a function is placed in hot
+// text, is determined by a CDF threshold over the profile weights. When the
+// profile is refreshed, edges and functions with weight close to the
+// threshold can flip state even though their behavior did not change. With
+// -d=pgodecisions, the decisions of a previous build (written with
+// -d=pgodecisionsout) are loaded, and an edge or function only flips state if
+// its weight crosses the threshold by more than -d=pgohysteresis percent.
+//
+// The decisions depend only on the profile and flags, not on the package
+// being compiled, so every compile of a build writes the same content. The
+// file is only written by compiles that actually run: if every package of a
+// build is found in the go build cache, it is not written, so go build -a
+// must be used to produce it. The go command includes the content of the
+// -d=pgodecisions file in the cache key, so an updated file is honored.
+
+// loadDecisions reads the previous decisions file, if any.
+func (p *Profile) loadDecisions() error {
+	if base.Debug.PGODecisions == "" {
+		return nil
+	}
+	f, err := os.Open(base.Debug.PGODecisions)
+	if err != nil {
+		return fmt.Errorf("error opening PGO decisions: %w", err)
+	}
+	defer f.Close()
+	p.prevDecisions, err = pgo.ReadDecisions(bufio.NewReader(f))
+	if err != nil {
+		return fmt.Errorf("error processing PGO decisions %s: %w", base.Debug.PGODecisions, err)
+	}
+	return nil
+}
+
+// HotEdges applies hysteresis against the previous decisions to hot, the
+// call edges that the inliner considers hot by CDF threshold, and records
+// the result as the decisions of this build.
+func (p *Profile) HotEdges(hot []pgo.NamedCallEdge) []pgo.NamedCallEdge {
+	if p.prevDecisions != nil {
+		hot = pgo.Hysteresis(hot, p.NamedEdgeMap.ByWeight, p.NamedEdgeMap.Weight, p.prevDecisions.HotEdges, float64(base.Debug.PGOHysteresis))
+	}
+	for _, e := range hot {
+		p.decisions.HotEdges[e] = true
+	}
+	return hot
+}
+
+// hotFuncs is like HotEdges, for functions placed in hot text. byWeight
+// lists all functions from highest to lowest entry weight.
+func (p *Profile) hotFuncs(hot, byWeight []string) []string {
+	if p.prevDecisions != nil {
+		hot = pgo.Hysteresis(hot, byWeight, p.funcWeights, p.prevDecisions.HotFuncs, float64(base.Debug.PGOHysteresis))
+	}
+	for _, name := range hot {
+		p.decisions.HotFuncs[name] = true
+	}
+	return hot
+}
+
+// WriteDecisions writes the decisions of this build to the file named by
+// -d=pgodecisionsout, if any. As concurrent compiles may write the same
+// file, the content is written to a temporary file that is then renamed.
+func (p *Profile) WriteDecisions() error {
+	if p == nil || base.Debug.PGODecisionsOut == "" {
+		return nil
+	}
+	out := base.Debug.PGODecisionsOut
+	f, err := os.CreateTemp(filepath.Dir(out), filepath.Base(out)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := p.decisions.WriteTo(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), out)
+}
@@ -115,6 +115,18 @@ type Profile struct {
 	// WeightedCG represents the IRGraph built from profile, which we will
 	// update as part of inlining.
 	WeightedCG *IRGraph
+
+	// funcWeights is the entry weight of each function in the profile,
+	// keyed by linker symbol name.
+	funcWeights map[string]int64
+
+	// hotText is the set of functions placed in hot text.
+	hotText map[string]struct{}
+
+	// prevDecisions are the hot/cold decisions of a previous build, if
+	// any, and decisions those of this build.
+	prevDecisions *pgo.Decisions
+	decisions     *pgo.Decisions
 }
 
 // New generates a profile-graph from the profile or pre-processed profile.
@@ -151,10 +163,21 @@ func New(profileFile string) (*Profile, error) {
 	// Create package-level call graph with weights from profile and IR.
 	wg := createIRGraph(base.NamedEdgeMap)
 
-	return &Profile{
+	p := &Profile{
 		Profile:    base,
 		WeightedCG: wg,
-	}, nil
+		decisions:  pgo.NewDecisions(),
+	}
+	if err := p.loadDecisions(); err != nil {
+		return nil, err
+	}
+	p.reportClampedOffsets(profileFile)
+	if err := p.checkMatchRate(); err != nil {
+		return nil, err
+	}
+	p.initTextLayout()
+
+	return p, nil
 }
 
 // initializeIRGraph builds the IRGraph by visiting all the ir.Func in decl list
@@ -224,12 +247,23 @@ func createIRGraphEdge(fn *ir.Func, callernode *IRNode, name string, namedEdgeMa
 	})
 }
 
-// NodeLineOffset returns the line offset of n in fn.
+// reportClampedOffsets warns, with -d=pgodebug, about profile edges whose
+// call site offsets were out of range. Such edges are unlikely to match any
+// call site.
+func (p *Profile) reportClampedOffsets(profileFile string) {
+	if n := p.ClampedCallSiteOffsets; n > 0 && base.Debug.PGODebug > 0 {
+		fmt.Printf("%s: warning: %d call edges with call site offsets out of range, clamped to ±%d\n", profileFile, n, pgo.MaxCallSiteOffset)
+	}
+}
+
+// NodeLineOffset returns the line offset of n in fn, clamped like the
+// offsets in the profile.
 func NodeLineOffset(n ir.Node, fn *ir.Func) int {
 	// See "A note on line numbers" at the top of the file.
-	line := int(base.Ctxt.InnermostPos(n.Pos()).RelLine())
-	startLine := int(base.Ctxt.InnermostPos(fn.Pos()).RelLine())
-	return line - startLine
+	line := int64(base.Ctxt.InnermostPos(n.Pos()).RelLine())
+	startLine := int64(base.Ctxt.InnermostPos(fn.Pos()).RelLine())
+	off, _ := pgo.ClampCallSiteOffset(line - startLine)
+	return off
 }
 
 // addIREdge adds an edge between caller and new node that points to `callee`
@@ -0,0 +1,64 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgoir
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/internal/objabi"
+	"cmd/internal/pgo"
+	"fmt"
+	"strings"
+)
+
+// Profile match rate.
+//
+// A profile of a different program, or of a much older version of the
+// program, names functions of the package being compiled that no longer
+// exist. The match rate is the percentage of the weight of the call edges
+// from functions of the package that are attributed to functions found in
+// the package IR. It is printed with -d=pgodebug, and with
+// -d=pgomatchfloor=N the compile fails if it is below N percent.
+//
+// Packages that do not appear in the profile at all have no match rate, as
+// most packages of a program are not hot.
+
+// matchWeights returns the total weight of the call edges whose caller
+// belongs to the package being compiled, and the part of it whose caller is
+// found in the package IR.
+func (p *Profile) matchWeights() (pkgWeight, matched int64) {
+	prefix := objabi.PathToPrefix(base.Ctxt.Pkgpath) + "."
+	for e, w := range p.NamedEdgeMap.Weight {
+		// Profiles name instantiated generic functions with "[...]"
+		// rather than their shape arguments, so they can't be found.
+		if !strings.HasPrefix(e.CallerName, prefix) || strings.Contains(e.CallerName, "[") {
+			continue
+		}
+		pkgWeight += w
+		if n := p.WeightedCG.IRNodes[e.CallerName]; n != nil && n.AST != nil {
+			matched += w
+		}
+	}
+	return pkgWeight, matched
+}
+
+// checkMatchRate reports the match rate of the profile, and returns an
+// error if it is below -d=pgomatchfloor.
+func (p *Profile) checkMatchRate() error {
+	if base.Debug.PGODebug == 0 && base.Debug.PGOMatchFloor == 0 {
+		return nil
+	}
+	pkgWeight, matched := p.matchWeights()
+	if pkgWeight == 0 {
+		return nil
+	}
+	rate := pgo.WeightInPercentage(matched, pkgWeight)
+	if base.Debug.PGODebug > 0 {
+		fmt.Printf("pgo match rate: %.2f%% (package has %.2f%% of profile weight)\n", rate, pgo.WeightInPercentage(pkgWeight, p.TotalWeight))
+	}
+	if rate < float64(base.Debug.PGOMatchFloor) {
+		return fmt.Errorf("only %.2f%% of the profile weight of package %s matches its functions, below -d=pgomatchfloor=%d; is the profile from a different program?", rate, base.Ctxt.Pkgpath, base.Debug.PGOMatchFloor)
+	}
+	return nil
+}
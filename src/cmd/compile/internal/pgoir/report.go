@@ -0,0 +1,143 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgoir
+
+import (
+	"cmd/compile/internal/base"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Report of PGO decisions.
+//
+// With -d=pgoreport=file, the profile-guided optimizations record each
+// decision they make, whether applied or skipped, and the decisions of the
+// package are written to file as JSON at the end of the compile. This is
+// meant for tools, as an alternative to scraping -m and -d=pgodebug output.
+//
+// If file is a directory, the report is written to a file in it named after
+// the package path, so that a single flag can be passed to every compile of
+// a build.
+
+// Decision kinds.
+const (
+	DecisionInline       = "inline"       // inlining of a hot call site
+	DecisionDevirtualize = "devirtualize" // devirtualization of an indirect call
+	DecisionLayout       = "layout"       // text placement of a function
+)
+
+// A Decision is a profile-guided optimization decision.
+type Decision struct {
+	Kind string `json:"kind"`
+
+	// Pos is the position of the call site, or of the function for
+	// layout decisions.
+	Pos string `json:"pos"`
+
+	// Func is the function containing Pos.
+	Func string `json:"func"`
+
+	// Callee is the callee of the call site, if any.
+	Callee string `json:"callee,omitempty"`
+
+	// Weight is the profile weight of the call edge, if known.
+	Weight int64 `json:"weight,omitempty"`
+
+	// Applied reports whether the optimization was performed.
+	Applied bool `json:"applied"`
+
+	// Reason describes the decision, such as why it was skipped.
+	Reason string `json:"reason,omitempty"`
+}
+
+var report struct {
+	sync.Mutex
+	decisions map[decisionKey]Decision
+}
+
+type decisionKey struct {
+	kind, pos, callee string
+}
+
+// Report records d in the -d=pgoreport report, if enabled. A call site may
+// be considered several times, so a later decision on the same call site
+// replaces an earlier one, except that a skipped decision never replaces
+// an applied one. Report may be called concurrently.
+func Report(d Decision) {
+	if base.Debug.PGOReport == "" {
+		return
+	}
+	report.Lock()
+	defer report.Unlock()
+	if report.decisions == nil {
+		report.decisions = make(map[decisionKey]Decision)
+	}
+	k := decisionKey{d.Kind, d.Pos, d.Callee}
+	if old, ok := report.decisions[k]; ok && old.Applied && !d.Applied {
+		return
+	}
+	report.decisions[k] = d
+}
+
+// WriteReport writes the report to the file named by -d=pgoreport, if any.
+// Decisions are sorted by position so that the output is deterministic.
+func WriteReport() error {
+	if base.Debug.PGOReport == "" {
+		return nil
+	}
+
+	report.Lock()
+	decisions := make([]Decision, 0, len(report.decisions))
+	for _, d := range report.decisions {
+		decisions = append(decisions, d)
+	}
+	report.Unlock()
+	sort.Slice(decisions, func(i, j int) bool {
+		di, dj := decisions[i], decisions[j]
+		if di.Pos != dj.Pos {
+			return di.Pos < dj.Pos
+		}
+		if di.Kind != dj.Kind {
+			return di.Kind < dj.Kind
+		}
+		return di.Callee < dj.Callee
+	})
+
+	out := struct {
+		Package   string     `json:"package"`
+		Profile   string     `json:"profile"`
+		Decisions []Decision `json:"decisions"`
+	}{base.Ctxt.Pkgpath, base.Flag.PgoProfile, decisions}
+	b, err := json.MarshalIndent(out, "", "\t")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	// As in WriteDecisions, write to a temporary file and rename, in
+	// case several compiles are given the same file.
+	name := base.Debug.PGOReport
+	if fi, err := os.Stat(name); err == nil && fi.IsDir() {
+		name = filepath.Join(name, url.PathEscape(base.Ctxt.Pkgpath)+".json")
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
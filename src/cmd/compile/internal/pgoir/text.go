@@ -0,0 +1,138 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgoir
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/internal/pgo"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TextClass describes where the linker should place the code of a function
+// relative to the rest of the text.
+type TextClass uint8
+
+const (
+	// TextDefault functions are laid out in the usual order.
+	TextDefault TextClass = iota
+
+	// TextHot functions are grouped together at the start of the text
+	// (.text.hot), so that the hot code occupies as few pages as possible.
+	TextHot
+
+	// TextUnlikely functions never appear in the profile and are grouped
+	// at the end of the text (.text.unlikely).
+	TextUnlikely
+)
+
+func (c TextClass) String() string {
+	switch c {
+	case TextHot:
+		return "hot"
+	case TextUnlikely:
+		return "unlikely"
+	}
+	return "default"
+}
+
+// Threshold in CDF percentage of function entry weight for hot text. For a
+// threshold of X the hottest functions that make up the top X% of total entry
+// weight are placed in hot text.
+var hotTextCDFThresholdPercent = float64(99)
+
+// initTextLayout computes the set of hot functions for text layout.
+func (p *Profile) initTextLayout() {
+	if base.Debug.PGOTextCDFThreshold != "" {
+		if s, err := strconv.ParseFloat(base.Debug.PGOTextCDFThreshold, 64); err == nil && s >= 0 && s <= 100 {
+			hotTextCDFThresholdPercent = s
+		} else {
+			base.Fatalf("invalid PGOTextCDFThreshold, must be between 0 and 100")
+		}
+	}
+
+	p.funcWeights = p.FuncEntryWeights()
+	p.hotText = make(map[string]struct{})
+	hot, byWeight := hotFuncsFromCDF(p.funcWeights, hotTextCDFThresholdPercent)
+	for _, name := range p.hotFuncs(hot, byWeight) {
+		p.hotText[name] = struct{}{}
+	}
+
+	if base.Debug.PGODebug >= 2 {
+		fmt.Printf("pgo text layout: %d hot functions (cdf threshold %v%%)\n", len(p.hotText), hotTextCDFThresholdPercent)
+	}
+}
+
+// hotFuncsFromCDF returns the names of the hottest functions by entry
+// weight that together make up the given percentage of the total entry
+// weight, hottest first, along with all the sampled functions in the same
+// order.
+func hotFuncsFromCDF(weights map[string]int64, threshold float64) (hot, byWeight []string) {
+	var total int64
+	names := make([]string, 0, len(weights))
+	for name, w := range weights {
+		if w == 0 {
+			continue
+		}
+		total += w
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if wi, wj := weights[names[i]], weights[names[j]]; wi != wj {
+			return wi > wj // want larger weight first
+		}
+		return names[i] < names[j]
+	})
+
+	cum := int64(0)
+	for i, name := range names {
+		cum += weights[name]
+		if pgo.WeightInPercentage(cum, total) > threshold {
+			// Include the function that makes it go over the
+			// threshold, as in hotNodesFromCDF in the inliner.
+			return names[:i+1], names
+		}
+	}
+	return names, names
+}
+
+// textClassesEnabled reports whether functions are classified for text
+// placement. The classes are recorded in the object file for the linker's
+// -hotcoldtext, and are also used by the flags that treat hot and cold
+// functions differently, so those imply the classification.
+func textClassesEnabled() bool {
+	return base.Debug.PGOTextLayout != 0 || base.Debug.PGOFuncAlign != 0 || base.Debug.PGODwarf != 0 || base.Debug.PadJumpsHotOnly != 0
+}
+
+// TextClass returns the text placement of fn according to the profile.
+func (p *Profile) TextClass(fn *ir.Func) TextClass {
+	if p == nil || !textClassesEnabled() {
+		return TextDefault
+	}
+	if _, ok := p.hotText[ir.LinkFuncName(fn)]; ok {
+		return TextHot
+	}
+	if p.NeverSampled(fn) {
+		return TextUnlikely
+	}
+	return TextDefault
+}
+
+// NeverSampled reports whether fn is known not to appear in the profile.
+func (p *Profile) NeverSampled(fn *ir.Func) bool {
+	if p == nil {
+		return false
+	}
+	name := ir.LinkFuncName(fn)
+	if _, ok := p.funcWeights[name]; ok {
+		return false
+	}
+	// Profiles name instantiated generic functions with "[...]" rather
+	// than their shape arguments, so a missing entry tells us nothing.
+	return !strings.Contains(name, "[")
+}
@@ -324,6 +324,30 @@ func Compile(fn *ir.Func, worker int, profile *pgoir.Profile) {
 		return
 	}
 
+	// Tell the linker where to place the function in the text, and
+	// spend extra alignment padding only on hot functions. Other
+	// functions keep the architecture's default alignment, which the
+	// assembler's jump padding on x86 relies on.
+	class := profile.TextClass(fn)
+	if class != pgoir.TextDefault {
+		pgoir.Report(pgoir.Decision{
+			Kind:    pgoir.DecisionLayout,
+			Pos:     ir.Line(fn),
+			Func:    ir.LinkFuncName(fn),
+			Applied: true,
+			Reason:  "text " + class.String(),
+		})
+	}
+	switch class {
+	case pgoir.TextHot:
+		fn.LSym.Set(obj.AttrHot, true)
+		if a := int32(base.Debug.PGOFuncAlign); a > fn.LSym.Func().Align {
+			fn.LSym.Func().Align = a
+		}
+	case pgoir.TextUnlikely:
+		fn.LSym.Set(obj.AttrCold, true)
+	}
+
 	pp.Flush() // assemble, fill in boilerplate, etc.
 
 	// If we're compiling the package init function, search for any
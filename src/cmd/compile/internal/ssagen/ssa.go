@@ -7365,6 +7365,14 @@ func genssa(f *ssa.Func, pp *objw.Progs) {
 			hotAlign = 64
 			hotRequire = 31
 		}
+		if a := int64(base.Debug.AlignHotBytes); a > 0 {
+			switch base.Ctxt.Arch.Name {
+			case "amd64", "386", "arm64":
+				// Pad whenever the header is not aligned.
+				hotAlign = a
+				hotRequire = a - 1
+			}
+		}
 	}
 
 	// Emit basic blocks
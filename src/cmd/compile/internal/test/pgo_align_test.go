@@ -0,0 +1,164 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"fmt"
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const pgoAlignSrc = `
+package main
+
+//go:noinline
+func hot(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += i * i
+	}
+	return s
+}
+
+func main() {
+	println(hot(10))
+}
+`
+
+const pgoAlignProfile = `GO PREPROFILE V1
+main.main
+main.hot
+1 100
+`
+
+// compilePGOAsm compiles src, with the profile prof if it is not empty,
+// and the given flags, and returns the assembly listing.
+func compilePGOAsm(t *testing.T, src, prof string, flags ...string) string {
+	dir := t.TempDir()
+	srcFile := filepath.Join(dir, "x.go")
+	if err := os.WriteFile(srcFile, []byte(src), 0644); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+
+	args := []string{"tool", "compile", "-p=main", "-S", "-o", filepath.Join(dir, "out.o")}
+	if prof != "" {
+		profFile := filepath.Join(dir, "x.pgo")
+		if err := os.WriteFile(profFile, []byte(prof), 0644); err != nil {
+			t.Fatalf("could not write file: %v", err)
+		}
+		args = append(args, "-pgoprofile="+profFile)
+	}
+	args = append(args, flags...)
+	cmd := testenv.Command(t, testenv.GoToolPath(t), append(args, srcFile)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go tool compile: %v\n%s", err, out)
+	}
+	return string(out)
+}
+
+// TestPGOAlignHotBytes tests that -d=alignhotbytes sets the alignment of
+// hot loop headers, and that it implies -d=alignhot.
+func TestPGOAlignHotBytes(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	switch runtime.GOARCH {
+	case "amd64", "386", "arm64":
+	default:
+		t.Skipf("hot loop alignment is not implemented on %s", runtime.GOARCH)
+	}
+	t.Parallel()
+
+	const want = "PCALIGNMAX\t$32, $31"
+	for _, flag := range []string{"-d=alignhotbytes=32", "-d=alignhot=0,alignhotbytes=32"} {
+		if out := compilePGOAsm(t, pgoAlignSrc, pgoAlignProfile, flag); !strings.Contains(out, want) {
+			t.Errorf("with %s, assembly does not contain %q:\n%s", flag, want, out)
+		}
+	}
+	if out := compilePGOAsm(t, pgoAlignSrc, pgoAlignProfile, "-d=alignhot=0"); strings.Contains(out, "PCALIGNMAX") {
+		t.Errorf("with -d=alignhot=0, hot loop header is aligned:\n%s", out)
+	}
+}
+
+// pgoPadJumpsSrc returns a program whose functions hot and cold have many
+// conditional jumps, so that some of them need padding against the JCC
+// erratum.
+func pgoPadJumpsSrc() string {
+	var body strings.Builder
+	for k := 0; k < 32; k++ {
+		fmt.Fprintf(&body, "\tif p[%d] == x {\n\t\ts += g(%d)\n\t}\n", k, k)
+	}
+	return `
+package main
+
+//go:noinline
+func g(k int) int { return k }
+
+//go:noinline
+func hot(p *[32]int, x int) int {
+	s := 0
+` + body.String() + `	return s
+}
+
+//go:noinline
+func cold(p *[32]int, x int) int {
+	s := 0
+` + body.String() + `	return s
+}
+
+func main() {
+	var p [32]int
+	println(hot(&p, 10) + cold(&p, 3))
+}
+`
+}
+
+var textSizeRE = regexp.MustCompile(`(?m)^(main\.\w+) STEXT .*size=(\d+)`)
+
+// textSizes returns the code size of each function in an assembly listing.
+func textSizes(t *testing.T, asm string) map[string]int {
+	sizes := make(map[string]int)
+	for _, m := range textSizeRE.FindAllStringSubmatch(asm, -1) {
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes[m[1]] = n
+	}
+	return sizes
+}
+
+// TestPGOPadJumpsHotOnly tests that -d=padjumpshotonly only pads jumps in
+// profile-hot functions, and pads all functions without a profile.
+func TestPGOPadJumpsHotOnly(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	if runtime.GOARCH != "amd64" {
+		t.Skipf("jump padding is only done on amd64")
+	}
+	t.Parallel()
+
+	src := pgoPadJumpsSrc()
+	padded := textSizes(t, compilePGOAsm(t, src, pgoAlignProfile))
+	hotOnly := textSizes(t, compilePGOAsm(t, src, pgoAlignProfile, "-d=padjumpshotonly=1"))
+	noProfile := textSizes(t, compilePGOAsm(t, src, "", "-d=padjumpshotonly=1"))
+
+	if padded["main.hot"] != padded["main.cold"] {
+		t.Fatalf("main.hot and main.cold differ in size by default: %d and %d", padded["main.hot"], padded["main.cold"])
+	}
+	if hotOnly["main.hot"] != padded["main.hot"] {
+		t.Errorf("with -d=padjumpshotonly=1, main.hot size %d, want %d", hotOnly["main.hot"], padded["main.hot"])
+	}
+	if hotOnly["main.cold"] >= padded["main.cold"] {
+		t.Errorf("with -d=padjumpshotonly=1, main.cold size %d, want less than %d", hotOnly["main.cold"], padded["main.cold"])
+	}
+	if noProfile["main.cold"] != padded["main.cold"] {
+		t.Errorf("with -d=padjumpshotonly=1 and no profile, main.cold size %d, want %d", noProfile["main.cold"], padded["main.cold"])
+	}
+}
@@ -6,11 +6,14 @@ package test
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"internal/testenv"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -325,3 +328,141 @@ func convertMultToGeneric(path string) error {
 
 	return os.WriteFile(path, content, 0644)
 }
+
+// devirtDecision is a devirtualization decision in a -d=pgoreport report.
+type devirtDecision struct {
+	pos, callee, reason string
+	applied             bool
+}
+
+// copyDevirtModule copies the devirtualize test module to a scratch
+// directory, adding a go.mod, and returns the directory.
+func copyDevirtModule(t *testing.T, pkg string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting wd: %v", err)
+	}
+	srcDir := filepath.Join(wd, "testdata", "pgo", "devirtualize")
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "mult.pkg"), 0755); err != nil {
+		t.Fatalf("error creating dir: %v", err)
+	}
+	for _, file := range []string{"devirt.go", "devirt_test.go", profFileName, filepath.Join("mult.pkg", "mult.go")} {
+		if err := copyFile(filepath.Join(dir, file), filepath.Join(srcDir, file)); err != nil {
+			t.Fatalf("error copying %s: %v", file, err)
+		}
+	}
+	goMod := fmt.Sprintf("module %s\ngo 1.21\n", pkg)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatalf("error writing go.mod: %v", err)
+	}
+	return dir
+}
+
+// devirtReport builds the test of package pkg in dir with the profile and
+// extra compiler flags, and returns the devirtualization decisions of the
+// -d=pgoreport report of pkg, and the raw report.
+func devirtReport(t *testing.T, dir, pkg, flags string) (map[devirtDecision]bool, []byte) {
+	reportDir, err := os.MkdirTemp(dir, "report")
+	if err != nil {
+		t.Fatalf("error creating dir: %v", err)
+	}
+	gcflag := fmt.Sprintf("-gcflags=-pgoprofile=%s -d=pgoreport=%s %s", filepath.Join(dir, profFileName), reportDir, flags)
+	cmd := testenv.CleanCmdEnv(testenv.Command(t, testenv.GoToolPath(t), "test", "-c", "-o", filepath.Join(dir, "test.exe"), gcflag, "."))
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("error building test: %v\n%s", err, out)
+	}
+
+	b, err := os.ReadFile(filepath.Join(reportDir, url.PathEscape(pkg)+".json"))
+	if err != nil {
+		t.Fatalf("error reading report: %v", err)
+	}
+	var report struct {
+		Decisions []struct {
+			Kind    string
+			Pos     string
+			Callee  string
+			Applied bool
+			Reason  string
+		}
+	}
+	if err := json.Unmarshal(b, &report); err != nil {
+		t.Fatalf("error decoding report: %v\n%s", err, b)
+	}
+
+	got := make(map[devirtDecision]bool)
+	for _, d := range report.Decisions {
+		if d.Kind == "devirtualize" {
+			got[devirtDecision{d.Pos[strings.LastIndex(d.Pos, "/")+1:], d.Callee, d.Reason, d.Applied}] = true
+		}
+	}
+	return got, b
+}
+
+// TestPGODevirtualizeReport tests that -d=pgoreport reports hot indirect
+// calls that are not devirtualized, and why.
+func TestPGODevirtualizeReport(t *testing.T) {
+	testenv.MustHaveGoRun(t)
+	t.Parallel()
+
+	const pkg = "example.com/pgo/devirtualize"
+	dir := copyDevirtModule(t, pkg)
+
+	// As in TestLookupFuncGeneric, MultFn can no longer be found.
+	if err := convertMultToGeneric(filepath.Join(dir, "mult.pkg", "mult.go")); err != nil {
+		t.Fatalf("error editing mult.go: %v", err)
+	}
+
+	got, b := devirtReport(t, dir, pkg, "")
+	for _, want := range []devirtDecision{
+		// ExerciseFuncConcrete
+		{"devirt.go:173:15", pkg + "/mult%2epkg.MultFn", "callee IR not available", false},
+		{"devirt.go:173:36", pkg + ".AddFn", "", true},
+	} {
+		if !got[want] {
+			t.Errorf("report is missing %+v:\n%s", want, b)
+		}
+	}
+}
+
+// TestPGODevirtualizeNoInline tests that -d=pgodevirtnoinline devirtualizes
+// hot calls to a callee that cannot be inlined.
+func TestPGODevirtualizeNoInline(t *testing.T) {
+	testenv.MustHaveGoRun(t)
+	t.Parallel()
+
+	const pkg = "example.com/pgo/devirtualize"
+	dir := copyDevirtModule(t, pkg)
+
+	// Mark Add.Add noinline, replacing the blank line before it so that
+	// the positions in the profile still match.
+	file := filepath.Join(dir, "devirt.go")
+	src, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("error reading devirt.go: %v", err)
+	}
+	const old = "type Add struct{}\n\nfunc (Add) Add"
+	if !strings.Contains(string(src), old) {
+		t.Fatalf("devirt.go does not contain %q", old)
+	}
+	src = []byte(strings.Replace(string(src), old, "type Add struct{}\n//go:noinline\nfunc (Add) Add", 1))
+	if err := os.WriteFile(file, src, 0644); err != nil {
+		t.Fatalf("error writing devirt.go: %v", err)
+	}
+
+	for _, tc := range []struct {
+		flags string
+		want  devirtDecision
+	}{
+		// ExerciseIface
+		{"", devirtDecision{"devirt.go:101:39", pkg + ".Add.Add", "callee cannot be inlined", false}},
+		{"-d=pgodevirtnoinline=1", devirtDecision{"devirt.go:101:39", pkg + ".Add.Add", "", true}},
+	} {
+		got, b := devirtReport(t, dir, pkg, tc.flags)
+		if !got[tc.want] {
+			t.Errorf("with flags %q, report is missing %+v:\n%s", tc.flags, tc.want, b)
+		}
+	}
+}
@@ -7,10 +7,12 @@ package test
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"internal/profile"
 	"internal/testenv"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -365,3 +367,281 @@ func TestPGOHash(t *testing.T) {
 		t.Errorf("output contains unexpected source line, out:\n%s", out)
 	}
 }
+
+// TestPGOInlineCold tests that -d=pgoinlinecoldthresh and -pgocoldsize
+// prevent inlining of all but the cheapest callees at call sites missing from
+// the profile.
+func TestPGOInlineCold(t *testing.T) {
+	testenv.MustHaveGoRun(t)
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting wd: %v", err)
+	}
+	srcDir := filepath.Join(wd, "testdata/pgo/inline")
+
+	// Copy the module to a scratch location so we can add a go.mod.
+	dir := t.TempDir()
+
+	for _, file := range []string{"inline_hot.go", "inline_hot_test.go", profFile} {
+		if err := copyFile(filepath.Join(dir, file), filepath.Join(srcDir, file)); err != nil {
+			t.Fatalf("error copying %s: %v", file, err)
+		}
+	}
+
+	// Cold calls medium and tiny, neither of which appear in the profile.
+	// medium is cheap enough for regular inlining, but not at a cold call
+	// site.
+	const coldSrc = `package main
+
+func medium(x int) int {
+	for i := 0; i < x; i++ {
+		x += i * x
+		x ^= x >> 3
+		x += i * x
+		x ^= x >> 5
+	}
+	return x
+}
+
+func tiny(x int) int { return x + 1 }
+
+func Cold(x int) int {
+	return medium(x) + tiny(x)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "cold.go"), []byte(coldSrc), 0644); err != nil {
+		t.Fatalf("error writing cold.go: %v", err)
+	}
+
+	inlinedMedium := regexp.MustCompile(`cold\.go:16:15: inlining call to medium`)
+	inlinedTiny := regexp.MustCompile(`cold\.go:16:25: inlining call to tiny`)
+
+	gcflag := fmt.Sprintf("-m -pgoprofile=%s", profFile)
+	out := buildPGOInliningTest(t, dir, gcflag)
+	if !inlinedMedium.Match(out) || !inlinedTiny.Match(out) {
+		t.Errorf("medium and tiny not inlined without cold flags, out:\n%s", out)
+	}
+
+	for _, flag := range []string{"-d=pgoinlinecoldthresh=0", "-pgocoldsize"} {
+		out = buildPGOInliningTest(t, dir, gcflag+" "+flag)
+		if inlinedMedium.Match(out) {
+			t.Errorf("%s: medium inlined at cold call site, out:\n%s", flag, out)
+		}
+		if !inlinedTiny.Match(out) {
+			t.Errorf("%s: tiny not inlined at cold call site, out:\n%s", flag, out)
+		}
+	}
+}
+
+// TestPGOInlineScale tests that with -d=pgoinlinescale a lukewarm hot call
+// site gets a smaller inline budget than the hottest one.
+func TestPGOInlineScale(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	// big costs a few hundred, between the hot budget of the hottest
+	// call site and the scaled budget of the lukewarm one.
+	src := `package main
+
+func main() {
+	a := big(1)
+	b := big(2)
+	println(a, b)
+}
+
+func big(x int) int {
+` + strings.Repeat("\tx = x*3 + 1\n\tx ^= x >> 2\n", 40) + `	return x
+}
+`
+	// The call at offset 1 makes up 90% of the weight, and the one at
+	// offset 2 another 9%, within the default CDF threshold of 99%.
+	const prof = `GO PREPROFILE V1
+main.main
+main.big
+1 90
+main.main
+main.big
+2 9
+main.main
+main.main
+3 1
+`
+	dir := t.TempDir()
+	srcFile := filepath.Join(dir, "x.go")
+	if err := os.WriteFile(srcFile, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	profFile := filepath.Join(dir, "x.pgo")
+	if err := os.WriteFile(profFile, []byte(prof), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	compile := func(flags ...string) []byte {
+		args := []string{"tool", "compile", "-p=main", "-m", "-pgoprofile=" + profFile, "-o", filepath.Join(dir, "x.o")}
+		args = append(args, flags...)
+		cmd := testenv.Command(t, testenv.GoToolPath(t), append(args, srcFile)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("go tool compile: %v\n%s", err, out)
+		}
+		return out
+	}
+	inlinedHottest := regexp.MustCompile(`x\.go:4:10: inlining call to big`)
+	inlinedLukewarm := regexp.MustCompile(`x\.go:5:10: inlining call to big`)
+
+	out := compile()
+	if !inlinedHottest.Match(out) || !inlinedLukewarm.Match(out) {
+		t.Errorf("big not inlined at both hot call sites without -d=pgoinlinescale, out:\n%s", out)
+	}
+	out = compile("-d=pgoinlinescale=1")
+	if !inlinedHottest.Match(out) {
+		t.Errorf("big not inlined at the hottest call site with -d=pgoinlinescale, out:\n%s", out)
+	}
+	if inlinedLukewarm.Match(out) {
+		t.Errorf("big inlined at the lukewarm call site with -d=pgoinlinescale, out:\n%s", out)
+	}
+}
+
+// TestPGOReport tests that -d=pgoreport writes the PGO inlining decisions.
+func TestPGOReport(t *testing.T) {
+	testenv.MustHaveGoRun(t)
+	t.Parallel()
+
+	const pkg = "example.com/pgo/inline"
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting wd: %v", err)
+	}
+	srcDir := filepath.Join(wd, "testdata/pgo/inline")
+
+	// Copy the module to a scratch location so we can add a go.mod.
+	dir := t.TempDir()
+
+	for _, file := range []string{"inline_hot.go", "inline_hot_test.go", profFile} {
+		if err := copyFile(filepath.Join(dir, file), filepath.Join(srcDir, file)); err != nil {
+			t.Fatalf("error copying %s: %v", file, err)
+		}
+	}
+
+	type decision struct {
+		Kind    string
+		Pos     string
+		Callee  string
+		Applied bool
+		Reason  string
+	}
+	// buildReport builds the package and returns the decisions of its
+	// report. go test -c also compiles the test main package, so have each
+	// package write its own report in a report directory.
+	buildReport := func(name string) []decision {
+		reportDir := filepath.Join(dir, name)
+		if err := os.Mkdir(reportDir, 0777); err != nil {
+			t.Fatal(err)
+		}
+		gcflag := fmt.Sprintf("-pgoprofile=%s -d=pgoinlinebudget=160,pgoinlinecdfthreshold=90,pgoreport=%s", profFile, reportDir)
+		buildPGOInliningTest(t, dir, gcflag)
+
+		reportFile := filepath.Join(reportDir, url.PathEscape(pkg)+".json")
+		b, err := os.ReadFile(reportFile)
+		if err != nil {
+			t.Fatalf("error reading report: %v", err)
+		}
+		var report struct {
+			Package   string
+			Decisions []decision
+		}
+		if err := json.Unmarshal(b, &report); err != nil {
+			t.Fatalf("error decoding report: %v\n%s", err, b)
+		}
+		if report.Package != pkg {
+			t.Errorf("report package got %q want %q", report.Package, pkg)
+		}
+		return report.Decisions
+	}
+
+	decisions := buildReport("report")
+	found := false
+	for _, d := range decisions {
+		if d.Kind == "inline" && d.Applied && d.Callee == pkg+".(*BS).NS" && strings.HasSuffix(d.Pos, "inline_hot.go:81:19") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("report does not contain hot inlining of (*BS).NS at inline_hot.go:81:19:\n%+v", decisions)
+	}
+
+	// Make (*BS).NS not inlinable. Its hot call sites, one line further
+	// down now, are reported as skipped with the reason.
+	file := filepath.Join(dir, "inline_hot.go")
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = bytes.Replace(b, []byte("func (b *BS) NS("), []byte("//go:noinline\nfunc (b *BS) NS("), 1)
+	if err := os.WriteFile(file, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	decisions = buildReport("report-noinline")
+	found = false
+	for _, d := range decisions {
+		if d.Kind == "inline" && !d.Applied && d.Callee == pkg+".(*BS).NS" && strings.HasSuffix(d.Pos, "inline_hot.go:82:19") && strings.Contains(d.Reason, "not inlinable") && strings.Contains(d.Reason, "go:noinline") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("report does not contain skipped inlining of non-inlinable (*BS).NS at inline_hot.go:82:19:\n%+v", decisions)
+	}
+}
+
+// TestPGOMatchFloor tests that -d=pgomatchfloor fails the build when the
+// profile does not match the package functions.
+func TestPGOMatchFloor(t *testing.T) {
+	testenv.MustHaveGoRun(t)
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting wd: %v", err)
+	}
+	srcDir := filepath.Join(wd, "testdata/pgo/inline")
+
+	// Copy the module to a scratch location so we can add a go.mod.
+	dir := t.TempDir()
+
+	for _, file := range []string{"inline_hot.go", "inline_hot_test.go", profFile} {
+		if err := copyFile(filepath.Join(dir, file), filepath.Join(srcDir, file)); err != nil {
+			t.Fatalf("error copying %s: %v", file, err)
+		}
+	}
+
+	gcflag := fmt.Sprintf("-pgoprofile=%s -d=pgomatchfloor=90", profFile)
+	buildPGOInliningTest(t, dir, gcflag)
+
+	// Rename A, which makes up almost all of the profile weight.
+	for _, file := range []string{"inline_hot.go", "inline_hot_test.go"} {
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = bytes.ReplaceAll(b, []byte("A()"), []byte("Renamed()"))
+		if err := os.WriteFile(filepath.Join(dir, file), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "test", "-c", "-o", filepath.Join(dir, "test.exe"), "-gcflags="+gcflag)
+	cmd.Dir = dir
+	cmd = testenv.CleanCmdEnv(cmd)
+	t.Log(cmd)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("build succeeded, want match rate error; output:\n%s", out)
+	}
+	if !bytes.Contains(out, []byte("-d=pgomatchfloor=90")) {
+		t.Errorf("build output does not contain match rate error:\n%s", out)
+	}
+}
@@ -1911,6 +1911,17 @@
 //	    If file ends in a slash or names an existing directory,
 //	    the test is written to pkg.test in that directory.
 //
+//	-pgogen
+//	    Run the test binary with CPU profiling and merge the resulting
+//	    profile into default.pgo in the package directory, creating it
+//	    if needed, so that later builds use it for profile-guided
+//	    optimization (see 'go help build'). The profile is only written
+//	    if the tests pass, and is checked with 'go tool preprofile'
+//	    first. Only a single package may be tested. To profile just
+//	    the benchmarks, use -pgogen -run=^$ -bench=. and to start from
+//	    scratch, remove default.pgo first. Note that go build only
+//	    uses default.pgo in the directory of the main package.
+//
 // The test binary also accepts flags that control execution of the test; these
 // flags are also accessible by 'go test'. See 'go help testflag' for details.
 //
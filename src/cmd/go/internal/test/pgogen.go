@@ -0,0 +1,115 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"fmt"
+	"internal/profile"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/cfg"
+	"cmd/go/internal/load"
+)
+
+// pgoGenProfile is the CPU profile written by the test binary with -pgogen.
+var pgoGenProfile string
+
+// initPGOGen arranges for the test binary to write a CPU profile for
+// -pgogen. It must be run before the test arguments are used.
+func initPGOGen() {
+	if !testPGOGen {
+		return
+	}
+	if testC || testFuzz != "" {
+		base.Fatalf("cannot use -pgogen flag with -c or -fuzz flag")
+	}
+	if testCPUProfile != "" {
+		base.Fatalf("cannot use -pgogen flag with -cpuprofile flag")
+	}
+	if len(pkgs) != 1 {
+		base.Fatalf("cannot use -pgogen flag with multiple packages")
+	}
+	if cfg.BuildN {
+		return
+	}
+
+	dir, err := os.MkdirTemp("", "go-pgogen-")
+	if err != nil {
+		base.Fatalf("%v", err)
+	}
+	base.AtExit(func() { os.RemoveAll(dir) })
+	pgoGenProfile = filepath.Join(dir, "cpu.pprof")
+
+	// Flags must come before any non-flag arguments to the test binary.
+	testArgs = append([]string{"-test.cpuprofile=" + pgoGenProfile}, testArgs...)
+}
+
+// writePGOGen merges the CPU profile written by the test binary into
+// default.pgo in the directory of p, creating it if needed. The result is
+// run through preprofile first, so that default.pgo is only replaced by a
+// profile that builds can use.
+func writePGOGen(p *load.Package) {
+	if pgoGenProfile == "" || base.GetExitStatus() != 0 {
+		return
+	}
+
+	prof, err := readPGOProfile(pgoGenProfile)
+	if err != nil {
+		base.Fatalf("go: -pgogen: reading test CPU profile: %v", err)
+	}
+	target := filepath.Join(p.Dir, "default.pgo")
+	if _, err := os.Stat(target); err == nil {
+		old, err := readPGOProfile(target)
+		if err != nil {
+			base.Fatalf("go: -pgogen: %v", err)
+		}
+		prof, err = profile.Merge([]*profile.Profile{old, prof})
+		if err != nil {
+			base.Fatalf("go: -pgogen: merging with %s: %v", base.ShortPath(target), err)
+		}
+	}
+
+	f, err := os.CreateTemp(p.Dir, "default.pgo.*")
+	if err != nil {
+		base.Fatalf("go: -pgogen: %v", err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	err = prof.Write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		base.Fatalf("go: -pgogen: writing profile: %v", err)
+	}
+
+	cmd := exec.Command(base.Tool("preprofile"), "-i", tmp, "-o", os.DevNull)
+	if cfg.BuildX {
+		fmt.Fprintf(os.Stderr, "%s -i %s -o %s\n", cmd.Path, tmp, os.DevNull)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		base.Fatalf("go: -pgogen: preprofile failed: %v\n%s", err, out)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		base.Fatalf("go: -pgogen: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "go: wrote %s\n", base.ShortPath(target))
+}
+
+func readPGOProfile(name string) (*profile.Profile, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	prof, err := profile.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", base.ShortPath(name), err)
+	}
+	return prof, nil
+}
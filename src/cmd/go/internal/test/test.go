@@ -165,6 +165,17 @@ In addition to the build flags, the flags handled by 'go test' itself are:
 	    If file ends in a slash or names an existing directory,
 	    the test is written to pkg.test in that directory.
 
+	-pgogen
+	    Run the test binary with CPU profiling and merge the resulting
+	    profile into default.pgo in the package directory, creating it
+	    if needed, so that later builds use it for profile-guided
+	    optimization (see 'go help build'). The profile is only written
+	    if the tests pass, and is checked with 'go tool preprofile'
+	    first. Only a single package may be tested. To profile just
+	    the benchmarks, use -pgogen -run=^$ -bench=. and to start from
+	    scratch, remove default.pgo first. Note that go build only
+	    uses default.pgo in the directory of the main package.
+
 The test binary also accepts flags that control execution of the test; these
 flags are also accessible by 'go test'. See 'go help testflag' for details.
 
@@ -548,6 +559,7 @@ var (
 	testList         string                            // -list flag
 	testO            string                            // -o flag
 	testOutputDir    outputdirFlag                     // -outputdir flag
+	testPGOGen       bool                              // -pgogen flag
 	testShuffle      shuffleFlag                       // -shuffle flag
 	testTimeout      time.Duration                     // -timeout flag
 	testV            testVFlag                         // -v flag
@@ -757,6 +769,7 @@ func runTest(ctx context.Context, cmd *base.Command, args []string) {
 	if testProfile() != "" && len(pkgs) != 1 {
 		base.Fatalf("cannot use %s flag with multiple packages", testProfile())
 	}
+	initPGOGen()
 
 	if testO != "" {
 		if strings.HasSuffix(testO, "/") || strings.HasSuffix(testO, string(os.PathSeparator)) {
@@ -1042,6 +1055,9 @@ func runTest(ctx context.Context, cmd *base.Command, args []string) {
 	}
 
 	b.Do(ctx, root)
+	if testPGOGen {
+		writePGOGen(pkgs[0])
+	}
 }
 
 var windowsBadWords = []string{
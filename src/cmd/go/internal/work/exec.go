@@ -332,6 +332,11 @@ func (b *Builder) buildActionID(a *Action) cache.ActionID {
 		base.Fatalf("buildActionID: unknown build toolchain %q", cfg.BuildToolchainName)
 	case "gc":
 		fmt.Fprintf(h, "compile %s %q %q\n", b.toolID("compile"), forcedGcflags, p.Internal.Gcflags)
+		// The hot/cold decisions of a previous build are an input to
+		// the compile, so their content must be part of the action ID.
+		if file := gcDebugValue(str.StringList(forcedGcflags, p.Internal.Gcflags), "pgodecisions"); file != "" {
+			fmt.Fprintf(h, "pgodecisions %s\n", b.fileHash(file))
+		}
 		if len(p.SFiles) > 0 {
 			fmt.Fprintf(h, "asm %q %q %q\n", b.toolID("asm"), forcedAsmflags, p.Internal.Asmflags)
 		}
@@ -408,6 +413,30 @@ func (b *Builder) buildActionID(a *Action) cache.ActionID {
 	return h.Sum()
 }
 
+// gcDebugValue returns the value of the compiler debug setting name, as
+// set by -d flags in gcflags, or "" if it is not set.
+func gcDebugValue(gcflags []string, name string) string {
+	value := ""
+	for i := 0; i < len(gcflags); i++ {
+		var list string
+		switch f := gcflags[i]; {
+		case strings.HasPrefix(f, "-d="):
+			list = f[len("-d="):]
+		case f == "-d" && i+1 < len(gcflags):
+			i++
+			list = gcflags[i]
+		default:
+			continue
+		}
+		for _, setting := range strings.Split(list, ",") {
+			if k, v, ok := strings.Cut(setting, "="); ok && k == name {
+				value = v
+			}
+		}
+	}
+	return value
+}
+
 // needCgoHdr reports whether the actions triggered by this one
 // expect to be able to access the cgo-generated header file.
 func (b *Builder) needCgoHdr(a *Action) bool {
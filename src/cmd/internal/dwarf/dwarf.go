@@ -94,6 +94,7 @@ type FnState struct {
 	StartPos      src.Pos
 	Size          int64
 	External      bool
+	Hotness       uint8 // DW_AT_go_hotness value, or 0 to omit it
 	Scopes        []Scope
 	InlCalls      InlCalls
 	UseBASEntries bool
@@ -316,10 +317,17 @@ const (
 	DW_AT_go_package_name   = 0x2905 // Attribute for DW_TAG_compile_unit
 	DW_AT_go_dict_index     = 0x2906 // Attribute for DW_TAG_typedef_type, index of the dictionary entry describing the real type of this type shape
 	DW_AT_go_closure_offset = 0x2907 // Attribute for DW_TAG_variable, offset in the closure struct where this captured variable resides
+	DW_AT_go_hotness        = 0x2908 // Attribute for DW_TAG_subprogram, placement of the function by profile-guided optimization (GoHotness*)
 
 	DW_AT_internal_location = 253 // params and locals; not emitted
 )
 
+// Values of DW_AT_go_hotness.
+const (
+	GoHotnessHot      = 1 // hot in the profile, placed in hot text
+	GoHotnessUnlikely = 2 // never in the profile, placed in unlikely text
+)
+
 // Index into the abbrevs table below.
 const (
 	DW_ABRV_NULL = iota
@@ -354,6 +362,8 @@ const (
 	DW_ABRV_STRUCTTYPE
 	DW_ABRV_TYPEDECL
 	DW_ABRV_DICT_INDEX
+	DW_ABRV_FUNCTION_HOTNESS
+	DW_ABRV_FUNCTION_CONCRETE_HOTNESS
 	DW_ABRV_PUTVAR_START
 )
 
@@ -777,6 +787,35 @@ var abbrevs = []dwAbbrev{
 			{DW_AT_go_dict_index, DW_FORM_udata},
 		},
 	},
+
+	/* FUNCTION_HOTNESS */
+	{
+		DW_TAG_subprogram,
+		DW_CHILDREN_yes,
+		[]dwAttrForm{
+			{DW_AT_name, DW_FORM_string},
+			{DW_AT_low_pc, DW_FORM_addr},
+			{DW_AT_high_pc, DW_FORM_addr},
+			{DW_AT_frame_base, DW_FORM_block1},
+			{DW_AT_decl_file, DW_FORM_data4},
+			{DW_AT_decl_line, DW_FORM_udata},
+			{DW_AT_external, DW_FORM_flag},
+			{DW_AT_go_hotness, DW_FORM_data1},
+		},
+	},
+
+	/* FUNCTION_CONCRETE_HOTNESS */
+	{
+		DW_TAG_subprogram,
+		DW_CHILDREN_yes,
+		[]dwAttrForm{
+			{DW_AT_abstract_origin, DW_FORM_ref_addr},
+			{DW_AT_low_pc, DW_FORM_addr},
+			{DW_AT_high_pc, DW_FORM_addr},
+			{DW_AT_frame_base, DW_FORM_block1},
+			{DW_AT_go_hotness, DW_FORM_data1},
+		},
+	},
 }
 
 // GetAbbrev returns the contents of the .debug_abbrev section.
@@ -1258,7 +1297,13 @@ func PutConcreteFunc(ctxt Context, s *FnState, isWrapper bool) error {
 	if isWrapper {
 		abbrev = DW_ABRV_WRAPPER_CONCRETE
 	}
-	Uleb128put(ctxt, s.Info, int64(abbrev))
+	// The hotness variant has the same attributes followed by
+	// DW_AT_go_hotness, so abbrev keeps describing the children.
+	if !isWrapper && s.Hotness != 0 {
+		Uleb128put(ctxt, s.Info, DW_ABRV_FUNCTION_CONCRETE_HOTNESS)
+	} else {
+		Uleb128put(ctxt, s.Info, int64(abbrev))
+	}
 
 	// Abstract origin.
 	putattr(ctxt, s.Info, abbrev, DW_FORM_ref_addr, DW_CLS_REFERENCE, 0, s.Absfn)
@@ -1272,6 +1317,8 @@ func PutConcreteFunc(ctxt Context, s *FnState, isWrapper bool) error {
 
 	if isWrapper {
 		putattr(ctxt, s.Info, abbrev, DW_FORM_flag, DW_CLS_FLAG, int64(1), 0)
+	} else if s.Hotness != 0 {
+		putattr(ctxt, s.Info, abbrev, DW_FORM_data1, DW_CLS_CONSTANT, int64(s.Hotness), nil)
 	}
 
 	// Scopes
@@ -1304,7 +1351,12 @@ func PutDefaultFunc(ctxt Context, s *FnState, isWrapper bool) error {
 	if isWrapper {
 		abbrev = DW_ABRV_WRAPPER
 	}
-	Uleb128put(ctxt, s.Info, int64(abbrev))
+	// As in PutConcreteFunc, abbrev keeps describing the children.
+	if !isWrapper && s.Hotness != 0 {
+		Uleb128put(ctxt, s.Info, DW_ABRV_FUNCTION_HOTNESS)
+	} else {
+		Uleb128put(ctxt, s.Info, int64(abbrev))
+	}
 
 	name := s.Name
 	if strings.HasPrefix(name, `"".`) {
@@ -1326,6 +1378,9 @@ func PutDefaultFunc(ctxt Context, s *FnState, isWrapper bool) error {
 			ev = 1
 		}
 		putattr(ctxt, s.Info, abbrev, DW_FORM_flag, DW_CLS_FLAG, ev, 0)
+		if s.Hotness != 0 {
+			putattr(ctxt, s.Info, abbrev, DW_FORM_data1, DW_CLS_CONSTANT, int64(s.Hotness), nil)
+		}
 	}
 
 	// Scopes
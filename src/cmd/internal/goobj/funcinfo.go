@@ -21,6 +21,7 @@ type FuncInfo struct {
 	Locals    uint32
 	FuncID    abi.FuncID
 	FuncFlag  abi.FuncFlag
+	TextClass uint8 // placement in the text by the PGO profile (TextClass*)
 	StartLine int32
 	File      []CUFileIndex
 	InlTree   []InlTreeNode
@@ -40,8 +41,8 @@ func (a *FuncInfo) Write(w *bytes.Buffer) {
 	writeUint32(a.Locals)
 	writeUint8(uint8(a.FuncID))
 	writeUint8(uint8(a.FuncFlag))
+	writeUint8(a.TextClass)
 	writeUint8(0) // pad to uint32 boundary
-	writeUint8(0)
 	writeUint32(uint32(a.StartLine))
 
 	writeUint32(uint32(len(a.File)))
@@ -54,6 +55,13 @@ func (a *FuncInfo) Write(w *bytes.Buffer) {
 	}
 }
 
+// Values of FuncInfo.TextClass.
+const (
+	TextClassDefault  = iota // laid out in the usual order
+	TextClassHot             // hot in the profile, grouped at the start of the text
+	TextClassUnlikely        // never in the profile, grouped at the end of the text
+)
+
 // FuncInfoLengths is a cache containing a roadmap of offsets and
 // lengths for things within a serialized FuncInfo. Each length field
 // stores the number of items (e.g. files, inltree nodes, etc), and the
@@ -93,6 +101,8 @@ func (*FuncInfo) ReadFuncID(b []byte) abi.FuncID { return abi.FuncID(b[8]) }
 
 func (*FuncInfo) ReadFuncFlag(b []byte) abi.FuncFlag { return abi.FuncFlag(b[9]) }
 
+func (*FuncInfo) ReadTextClass(b []byte) uint8 { return b[10] }
+
 func (*FuncInfo) ReadStartLine(b []byte) int32 { return int32(binary.LittleEndian.Uint32(b[12:])) }
 
 func (*FuncInfo) ReadFile(b []byte, filesoff uint32, k uint32) CUFileIndex {
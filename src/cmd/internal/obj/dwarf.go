@@ -365,6 +365,14 @@ func (ctxt *Link) populateDWARF(curfn Func, s *LSym) {
 		InlCalls:      inlcalls,
 		UseBASEntries: ctxt.UseBASEntries,
 	}
+	if ctxt.Flag_dwarfhotness {
+		switch {
+		case s.Hot():
+			fnstate.Hotness = dwarf.GoHotnessHot
+		case s.Cold():
+			fnstate.Hotness = dwarf.GoHotnessUnlikely
+		}
+	}
 	if absfunc != nil {
 		err = dwarf.PutAbstractFunc(dwctxt, fnstate)
 		if err != nil {
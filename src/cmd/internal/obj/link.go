@@ -840,6 +840,12 @@ const (
 	// Linkname indicates this is a go:linkname'd symbol.
 	AttrLinkname
 
+	// Hot and Cold are set on function symbols that the profile marks as
+	// hot or never executed. The linker groups them together at the start
+	// and end of the text, respectively.
+	AttrHot
+	AttrCold
+
 	// attrABIBase is the value at which the ABI is encoded in
 	// Attribute. This must be last; all bits after this are
 	// assumed to be an ABI value.
@@ -870,6 +876,8 @@ func (a *Attribute) ABIWrapper() bool         { return a.load()&AttrABIWrapper !
 func (a *Attribute) IsPcdata() bool           { return a.load()&AttrPcdata != 0 }
 func (a *Attribute) IsPkgInit() bool          { return a.load()&AttrPkgInit != 0 }
 func (a *Attribute) IsLinkname() bool         { return a.load()&AttrLinkname != 0 }
+func (a *Attribute) Hot() bool                { return a.load()&AttrHot != 0 }
+func (a *Attribute) Cold() bool               { return a.load()&AttrCold != 0 }
 
 func (a *Attribute) Set(flag Attribute, value bool) {
 	for {
@@ -920,6 +928,8 @@ var textAttrStrings = [...]struct {
 	{bit: AttrABIWrapper, s: "ABIWRAPPER"},
 	{bit: AttrPkgInit, s: "PKGINIT"},
 	{bit: AttrLinkname, s: "LINKNAME"},
+	{bit: AttrHot, s: ""},
+	{bit: AttrCold, s: ""},
 }
 
 // String formats a for printing in as part of a TEXT prog.
@@ -1025,6 +1035,7 @@ type Link struct {
 	Flag_linkshared    bool
 	Flag_optimize      bool
 	Flag_locationlists bool
+	Flag_dwarfhotness  bool   // emit DW_AT_go_hotness for functions marked hot or cold
 	Flag_noRefName     bool   // do not include referenced symbol names in object file
 	Retpoline          bool   // emit use of retpoline stubs for indirect jmp/call
 	Flag_maymorestack  string // If not "", call this function before stack checks
@@ -1050,6 +1061,8 @@ type Link struct {
 	IsAsm         bool // is the source assembly language, which may contain surprising idioms (e.g., call tables)
 	Std           bool // is standard library package
 
+	PadJumpsHotOnly bool // amd64: only pad jumps against the JCC erratum in functions marked hot (-d=padjumpshotonly)
+
 	// state for writing objects
 	Text []*LSym
 	Data []*LSym
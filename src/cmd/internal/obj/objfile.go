@@ -762,6 +762,12 @@ func genFuncInfoSyms(ctxt *Link) {
 			FuncFlag:  fn.FuncFlag,
 			StartLine: fn.StartLine,
 		}
+		switch {
+		case s.Hot():
+			o.TextClass = goobj.TextClassHot
+		case s.Cold():
+			o.TextClass = goobj.TextClassUnlikely
+		}
 		pc := &fn.Pcln
 		i := 0
 		o.File = make([]goobj.CUFileIndex, len(pc.UsedFiles))
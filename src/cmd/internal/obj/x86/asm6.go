@@ -1977,7 +1977,7 @@ func fusedJump(p *obj.Prog) (bool, uint8) {
 
 type padJumpsCtx int32
 
-func makePjcCtx(ctxt *obj.Link) padJumpsCtx {
+func makePjcCtx(ctxt *obj.Link, s *obj.LSym) padJumpsCtx {
 	// Disable jump padding on 32 bit builds by setting
 	// padJumps to 0.
 	if ctxt.Arch.Family == sys.I386 {
@@ -1989,6 +1989,11 @@ func makePjcCtx(ctxt *obj.Link) padJumpsCtx {
 		return padJumpsCtx(0)
 	}
 
+	// Limit the code size growth to functions the profile marks hot.
+	if ctxt.PadJumpsHotOnly && !s.Hot() {
+		return padJumpsCtx(0)
+	}
+
 	return padJumpsCtx(32)
 }
 
@@ -2059,7 +2064,7 @@ func span6(ctxt *obj.Link, s *obj.LSym, newprog obj.ProgAlloc) {
 		ctxt.Retpoline = false // don't keep printing
 	}
 
-	pjc := makePjcCtx(ctxt)
+	pjc := makePjcCtx(ctxt, s)
 
 	if s.P != nil {
 		return
@@ -340,3 +340,25 @@ func TestPCALIGN(t *testing.T) {
 		}
 	}
 }
+
+func TestPadJumpsHotOnly(t *testing.T) {
+	hot := &obj.LSym{Name: "hot"}
+	hot.Set(obj.AttrHot, true)
+	cold := &obj.LSym{Name: "cold"}
+
+	for _, test := range []struct {
+		hotOnly bool
+		s       *obj.LSym
+		want    padJumpsCtx
+	}{
+		{false, hot, 32},
+		{false, cold, 32},
+		{true, hot, 32},
+		{true, cold, 0},
+	} {
+		ctxt := &obj.Link{Arch: &Linkamd64, PadJumpsHotOnly: test.hotOnly}
+		if got := makePjcCtx(ctxt, test.s); got != test.want {
+			t.Errorf("makePjcCtx(PadJumpsHotOnly=%v, %s) = %d, want %d", test.hotOnly, test.s.Name, got, test.want)
+		}
+	}
+}
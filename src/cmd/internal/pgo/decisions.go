@@ -0,0 +1,196 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Decisions records the hot/cold classification made from a profile: the
+// call edges considered hot for inlining and the functions placed in hot
+// text. A build can load the decisions of a previous build to apply
+// hysteresis, so that refreshing the profile does not flip the state of
+// edges and functions whose weight is close to the hot threshold.
+//
+// The format of the serialized output is as follows.
+//
+//	GO PGO DECISIONS V1
+//	edge
+//	caller_name
+//	callee_name
+//	call_site_offset
+//	func
+//	func_name
+//	...
+//
+// Entries are sorted, edges first.
+type Decisions struct {
+	HotEdges map[NamedCallEdge]bool
+	HotFuncs map[string]bool
+}
+
+const decisionsHeader = "GO PGO DECISIONS V1\n"
+
+// NewDecisions returns an empty set of decisions.
+func NewDecisions() *Decisions {
+	return &Decisions{
+		HotEdges: make(map[NamedCallEdge]bool),
+		HotFuncs: make(map[string]bool),
+	}
+}
+
+// ReadDecisions parses decisions from the serialization output of
+// Decisions.WriteTo.
+func ReadDecisions(r io.Reader) (*Decisions, error) {
+	d := NewDecisions()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanLines)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("error reading decisions: %w", err)
+		}
+		return nil, fmt.Errorf("decisions missing header")
+	}
+	if gotHdr := scanner.Text() + "\n"; gotHdr != decisionsHeader {
+		return nil, fmt.Errorf("decisions malformed header; got %q want %q", gotHdr, decisionsHeader)
+	}
+
+	next := func(what string) (string, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", fmt.Errorf("error reading decisions: %w", err)
+			}
+			return "", fmt.Errorf("decisions entry missing %s", what)
+		}
+		return scanner.Text(), nil
+	}
+
+	for scanner.Scan() {
+		switch kind := scanner.Text(); kind {
+		case "edge":
+			caller, err := next("caller")
+			if err != nil {
+				return nil, err
+			}
+			callee, err := next("callee")
+			if err != nil {
+				return nil, err
+			}
+			offStr, err := next("call site offset")
+			if err != nil {
+				return nil, err
+			}
+			off, err := strconv.Atoi(offStr)
+			if err != nil {
+				return nil, fmt.Errorf("decisions error processing call site offset: %w", err)
+			}
+			d.HotEdges[NamedCallEdge{CallerName: caller, CalleeName: callee, CallSiteOffset: off}] = true
+		case "func":
+			name, err := next("function name")
+			if err != nil {
+				return nil, err
+			}
+			d.HotFuncs[name] = true
+		default:
+			return nil, fmt.Errorf("decisions entry has unknown kind %q", kind)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading decisions: %w", err)
+	}
+	return d, nil
+}
+
+// WriteTo writes a serialized representation of d to w.
+//
+// ReadDecisions can parse the format back to Decisions.
+//
+// WriteTo implements io.WriterTo.Write.
+func (d *Decisions) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+
+	edges := make([]NamedCallEdge, 0, len(d.HotEdges))
+	for e := range d.HotEdges {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		ei, ej := edges[i], edges[j]
+		if ei.CallerName != ej.CallerName {
+			return ei.CallerName < ej.CallerName
+		}
+		if ei.CalleeName != ej.CalleeName {
+			return ei.CalleeName < ej.CalleeName
+		}
+		return ei.CallSiteOffset < ej.CallSiteOffset
+	})
+	funcs := make([]string, 0, len(d.HotFuncs))
+	for f := range d.HotFuncs {
+		funcs = append(funcs, f)
+	}
+	sort.Strings(funcs)
+
+	var written int64
+	n, err := bw.WriteString(decisionsHeader)
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+	for _, e := range edges {
+		n, err = fmt.Fprintf(bw, "edge\n%s\n%s\n%d\n", e.CallerName, e.CalleeName, e.CallSiteOffset)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	for _, f := range funcs {
+		n, err = fmt.Fprintf(bw, "func\n%s\n", f)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, bw.Flush()
+}
+
+// Hysteresis adjusts a hot set computed from a weight threshold against the
+// hot set of a previous build.
+//
+// hot is the current hot set, and byWeight lists all candidates sorted by
+// weight from highest to lowest. The lowest weight in hot is taken as the
+// threshold. A candidate that was hot in prev stays hot unless its weight
+// fell more than pct percent below the threshold; a candidate that was not
+// hot becomes hot only if its weight is more than pct percent above the
+// threshold.
+//
+// prev only records the entries that were hot, so a candidate missing from
+// prev, including one that did not exist in the previous build, is treated
+// as previously cold: it must clear the upper band to become hot. This
+// keeps the hot set stable at the cost of delaying new entries whose weight
+// is just above the threshold until they are clearly hot.
+//
+// The result is sorted by weight from highest to lowest.
+func Hysteresis[K comparable](hot, byWeight []K, weight map[K]int64, prev map[K]bool, pct float64) []K {
+	if len(hot) == 0 || pct <= 0 {
+		return hot
+	}
+	threshold := float64(weight[hot[len(hot)-1]])
+	low := threshold * (1 - pct/100)
+	high := threshold * (1 + pct/100)
+
+	var res []K
+	for _, k := range byWeight {
+		w := float64(weight[k])
+		if prev[k] && w >= low || !prev[k] && w >= high {
+			res = append(res, k)
+		}
+	}
+	return res
+}
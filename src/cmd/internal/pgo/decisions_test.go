@@ -0,0 +1,103 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgo
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecisionsRoundTrip(t *testing.T) {
+	d := NewDecisions()
+	d.HotEdges[NamedCallEdge{CallerName: "a", CalleeName: "b", CallSiteOffset: 3}] = true
+	d.HotEdges[NamedCallEdge{CallerName: "a", CalleeName: "b", CallSiteOffset: 1}] = true
+	d.HotEdges[NamedCallEdge{CallerName: "type:.eq.struct { a int }", CalleeName: "c", CallSiteOffset: 0}] = true
+	d.HotFuncs["main.main"] = true
+	d.HotFuncs["example.com/foo.(*T).M"] = true
+
+	var buf bytes.Buffer
+	n, err := d.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo got err %v want nil", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo got n %d want %d", n, int64(buf.Len()))
+	}
+
+	got, err := ReadDecisions(&buf)
+	if err != nil {
+		t.Fatalf("ReadDecisions got err %v want nil", err)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("round trip got %+v want %+v", got, d)
+	}
+}
+
+func TestDecisionsMalformed(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"header", "GO PREPROFILE V1\n"},
+		{"kind", decisionsHeader + "block\n"},
+		{"truncated edge", decisionsHeader + "edge\na\nb\n"},
+		{"bad offset", decisionsHeader + "edge\na\nb\nx\n"},
+		{"truncated func", decisionsHeader + "func\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ReadDecisions(strings.NewReader(tc.in)); err == nil {
+				t.Errorf("ReadDecisions(%q) got nil err want non-nil", tc.in)
+			}
+		})
+	}
+}
+
+func TestHysteresis(t *testing.T) {
+	weight := map[string]int64{
+		"a": 1000,
+		"b": 110, // previously cold, just above the threshold
+		"c": 100, // threshold
+		"d": 90,  // previously hot, just below the threshold
+		"e": 50,  // previously hot, well below the threshold
+		"f": 130, // previously cold, well above the threshold
+	}
+	byWeight := []string{"a", "f", "b", "c", "d", "e"}
+	hot := []string{"a", "f", "b", "c"}
+	prev := map[string]bool{"a": true, "c": true, "d": true, "e": true}
+
+	got := Hysteresis(hot, byWeight, weight, prev, 20)
+	want := []string{"a", "f", "c", "d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hysteresis got %v want %v", got, want)
+	}
+
+	if got := Hysteresis(hot, byWeight, weight, prev, 0); !reflect.DeepEqual(got, hot) {
+		t.Errorf("Hysteresis with pct 0 got %v want %v", got, hot)
+	}
+}
+
+func TestHysteresisNewEntries(t *testing.T) {
+	// Entries missing from prev (here, new functions) within the
+	// band around the threshold are not hot, even the threshold
+	// entry itself; entries above the band are.
+	weight := map[string]int64{
+		"old":  1000,
+		"new1": 200, // above the band
+		"new2": 110, // in the band, above the threshold
+		"new3": 100, // threshold
+	}
+	byWeight := []string{"old", "new1", "new2", "new3"}
+	hot := []string{"old", "new1", "new2", "new3"}
+	prev := map[string]bool{"old": true, "gone": true}
+
+	got := Hysteresis(hot, byWeight, weight, prev, 20)
+	want := []string{"old", "new1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hysteresis got %v want %v", got, want)
+	}
+}
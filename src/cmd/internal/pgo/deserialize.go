@@ -30,52 +30,98 @@ func IsSerialized(r *bufio.Reader) (bool, error) {
 
 // FromSerialized parses a profile from serialization output of Profile.WriteTo.
 func FromSerialized(r io.Reader) (*Profile, error) {
+	var first error
+	d := parseSerialized(r, func(line int, err error) bool {
+		first = err
+		return false
+	})
+	if first != nil {
+		return nil, first
+	}
+	return d, nil
+}
+
+// ValidateSerialized is like FromSerialized, but rather than stopping at the
+// first malformed entry, it skips it and continues. It returns the profile
+// made of the well-formed entries, and an error for each problem found,
+// prefixed with its line number.
+func ValidateSerialized(r io.Reader) (*Profile, []error) {
+	var errs []error
+	d := parseSerialized(r, func(line int, err error) bool {
+		errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+		return true
+	})
+	return d, errs
+}
+
+// parseSerialized parses a profile from serialization output of
+// Profile.WriteTo. It calls report with the line number of each problem
+// found, and stops parsing if report returns false. An entry with a problem
+// is not added to the profile.
+func parseSerialized(r io.Reader, report func(line int, err error) bool) *Profile {
 	d := emptyProfile()
 
 	scanner := bufio.NewScanner(r)
 	scanner.Split(bufio.ScanLines)
+	line := 0
+	scan := func() bool {
+		line++
+		return scanner.Scan()
+	}
 
-	if !scanner.Scan() {
+	if !scan() {
 		if err := scanner.Err(); err != nil {
-			return nil, fmt.Errorf("error reading preprocessed profile: %w", err)
+			report(line, fmt.Errorf("error reading preprocessed profile: %w", err))
+			return d
 		}
-		return nil, fmt.Errorf("preprocessed profile missing header")
+		report(line, fmt.Errorf("preprocessed profile missing header"))
+		return d
 	}
 	if gotHdr := scanner.Text() + "\n"; gotHdr != serializationHeader {
-		return nil, fmt.Errorf("preprocessed profile malformed header; got %q want %q", gotHdr, serializationHeader)
+		report(line, fmt.Errorf("preprocessed profile malformed header; got %q want %q", gotHdr, serializationHeader))
+		return d
 	}
 
-	for scanner.Scan() {
-		readStr := scanner.Text()
-
-		callerName := readStr
+	for scan() {
+		callerName := scanner.Text()
 
-		if !scanner.Scan() {
+		if !scan() {
 			if err := scanner.Err(); err != nil {
-				return nil, fmt.Errorf("error reading preprocessed profile: %w", err)
+				report(line, fmt.Errorf("error reading preprocessed profile: %w", err))
+				return d
 			}
-			return nil, fmt.Errorf("preprocessed profile entry missing callee")
+			report(line, fmt.Errorf("preprocessed profile entry missing callee"))
+			return d
 		}
 		calleeName := scanner.Text()
 
-		if !scanner.Scan() {
+		if !scan() {
 			if err := scanner.Err(); err != nil {
-				return nil, fmt.Errorf("error reading preprocessed profile: %w", err)
+				report(line, fmt.Errorf("error reading preprocessed profile: %w", err))
+				return d
 			}
-			return nil, fmt.Errorf("preprocessed profile entry missing weight")
+			report(line, fmt.Errorf("preprocessed profile entry missing weight"))
+			return d
 		}
-		readStr = scanner.Text()
+		readStr := scanner.Text()
 
 		split := strings.Split(readStr, " ")
 
 		if len(split) != 2 {
-			return nil, fmt.Errorf("preprocessed profile entry got %v want 2 fields", split)
+			if !report(line, fmt.Errorf("preprocessed profile entry got %v want 2 fields", split)) {
+				return d
+			}
+			continue
 		}
 
-		co, err := strconv.Atoi(split[0])
+		co64, err := strconv.ParseInt(split[0], 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("preprocessed profile error processing call line: %w", err)
+			if !report(line, fmt.Errorf("preprocessed profile error processing call line: %w", err)) {
+				return d
+			}
+			continue
 		}
+		co, clamped := ClampCallSiteOffset(co64)
 
 		edge := NamedCallEdge{
 			CallerName:     callerName,
@@ -85,18 +131,29 @@ func FromSerialized(r io.Reader) (*Profile, error) {
 
 		weight, err := strconv.ParseInt(split[1], 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("preprocessed profile error processing call weight: %w", err)
+			if !report(line, fmt.Errorf("preprocessed profile error processing call weight: %w", err)) {
+				return d
+			}
+			continue
 		}
 
 		if _, ok := d.NamedEdgeMap.Weight[edge]; ok {
-			return nil, fmt.Errorf("preprocessed profile contains duplicate edge %+v", edge)
+			if !report(line, fmt.Errorf("preprocessed profile contains duplicate edge %+v", edge)) {
+				return d
+			}
+			continue
 		}
 
+		if clamped {
+			d.ClampedCallSiteOffsets++
+		}
 		d.NamedEdgeMap.ByWeight = append(d.NamedEdgeMap.ByWeight, edge) // N.B. serialization is ordered.
 		d.NamedEdgeMap.Weight[edge] += weight
 		d.TotalWeight += weight
 	}
+	if err := scanner.Err(); err != nil {
+		report(line, fmt.Errorf("error reading preprocessed profile: %w", err))
+	}
 
-	return d, nil
-
+	return d
 }
@@ -0,0 +1,91 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgo
+
+import "sort"
+
+// FuncCluster is a group of functions connected by hot call edges, which
+// should be placed next to each other in the text.
+type FuncCluster struct {
+	// Funcs are the linker symbol names of the functions, in layout
+	// order.
+	Funcs []string
+
+	// Weight is the sum of the entry weights of Funcs.
+	Weight int64
+}
+
+// maxClusterFuncs limits the number of functions in a cluster. The profile
+// does not record function sizes, so this is a rough proxy for keeping a
+// cluster within a few pages.
+const maxClusterFuncs = 64
+
+// FuncOrder returns the functions of the profile grouped into clusters, in
+// decreasing order of cluster weight.
+//
+// Clusters are formed by visiting call edges from heaviest to lightest and
+// appending the cluster of the callee to the cluster of the caller, so that
+// callees follow their hottest callers, as long as the merged cluster is
+// not too large.
+func (p *Profile) FuncOrder() []FuncCluster {
+	weights := p.FuncEntryWeights()
+
+	clusters := make(map[string]*FuncCluster, len(weights))
+	for name, w := range weights {
+		clusters[name] = &FuncCluster{Funcs: []string{name}, Weight: w}
+	}
+
+	// Aggregate edges across call sites.
+	type pair struct{ caller, callee string }
+	edgeWeights := make(map[pair]int64)
+	for e, w := range p.NamedEdgeMap.Weight {
+		if e.CallerName == e.CalleeName {
+			continue
+		}
+		edgeWeights[pair{e.CallerName, e.CalleeName}] += w
+	}
+	edges := make([]pair, 0, len(edgeWeights))
+	for e := range edgeWeights {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		ei, ej := edges[i], edges[j]
+		if wi, wj := edgeWeights[ei], edgeWeights[ej]; wi != wj {
+			return wi > wj // want larger weight first
+		}
+		if ei.caller != ej.caller {
+			return ei.caller < ej.caller
+		}
+		return ei.callee < ej.callee
+	})
+
+	for _, e := range edges {
+		a, b := clusters[e.caller], clusters[e.callee]
+		if a == b || len(a.Funcs)+len(b.Funcs) > maxClusterFuncs {
+			continue
+		}
+		a.Funcs = append(a.Funcs, b.Funcs...)
+		a.Weight += b.Weight
+		for _, name := range b.Funcs {
+			clusters[name] = a
+		}
+	}
+
+	seen := make(map[*FuncCluster]bool)
+	var order []FuncCluster
+	for _, c := range clusters {
+		if !seen[c] {
+			seen[c] = true
+			order = append(order, *c)
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if wi, wj := order[i].Weight, order[j].Weight; wi != wj {
+			return wi > wj
+		}
+		return order[i].Funcs[0] < order[j].Funcs[0]
+	})
+	return order
+}
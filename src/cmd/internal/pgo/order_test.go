@@ -0,0 +1,35 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuncOrder(t *testing.T) {
+	p := emptyProfile()
+	add := func(caller, callee string, offset int, w int64) {
+		e := NamedCallEdge{CallerName: caller, CalleeName: callee, CallSiteOffset: offset}
+		p.NamedEdgeMap.Weight[e] += w
+		p.TotalWeight += w
+	}
+	// main -> hot -> leaf is the hot chain, split across two call sites.
+	add("main", "hot", 1, 60)
+	add("main", "hot", 2, 40)
+	add("hot", "leaf", 1, 80)
+	add("hot", "hot", 3, 5) // recursion does not affect clustering
+	// other -> warm is a separate, lighter chain.
+	add("other", "warm", 1, 10)
+
+	got := p.FuncOrder()
+	want := []FuncCluster{
+		{Funcs: []string{"main", "hot", "leaf"}, Weight: 185},
+		{Funcs: []string{"other", "warm"}, Weight: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FuncOrder got %+v want %+v", got, want)
+	}
+}
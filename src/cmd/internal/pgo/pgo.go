@@ -17,6 +17,10 @@ type Profile struct {
 	// NamedEdgeMap contains all unique call edges in the profile and their
 	// edge weight.
 	NamedEdgeMap NamedEdgeMap
+
+	// ClampedCallSiteOffsets is the number of call edges whose call site
+	// offset was out of range and was clamped by ClampCallSiteOffset.
+	ClampedCallSiteOffsets int
 }
 
 // NamedCallEdge identifies a call edge by linker symbol names and call site
@@ -27,6 +31,28 @@ type NamedCallEdge struct {
 	CallSiteOffset int // Line offset from function start line.
 }
 
+// MaxCallSiteOffset is the largest magnitude of a CallSiteOffset.
+//
+// Offsets are the difference between the line of a call and the start line
+// of its function. They may be negative when //line directives move the
+// call before the function start. Offsets beyond MaxCallSiteOffset can only
+// come from pathological inputs, and would not be representable in an int
+// on 32-bit hosts. They are clamped to the range, so that profile entries
+// and IR call sites are compared with the same arithmetic on all hosts.
+const MaxCallSiteOffset = 1<<31 - 1
+
+// ClampCallSiteOffset clamps off to [-MaxCallSiteOffset, MaxCallSiteOffset].
+// It reports whether off was out of range.
+func ClampCallSiteOffset(off int64) (int, bool) {
+	switch {
+	case off > MaxCallSiteOffset:
+		return MaxCallSiteOffset, true
+	case off < -MaxCallSiteOffset:
+		return -MaxCallSiteOffset, true
+	}
+	return int(off), false
+}
+
 // NamedEdgeMap contains all unique call edges in the profile and their
 // edge weight.
 type NamedEdgeMap struct {
@@ -53,3 +79,18 @@ func WeightInPercentage(value int64, total int64) float64 {
 	return (float64(value) / float64(total)) * 100
 }
 
+// FuncEntryWeights returns the entry weight of every function that appears
+// in the profile, keyed by linker symbol name. The entry weight of a function
+// is the sum of the weights of all call edges into it. Functions that only
+// appear as callers are included with weight 0, so the presence of a key
+// indicates that the function was sampled at all.
+func (p *Profile) FuncEntryWeights() map[string]int64 {
+	weights := make(map[string]int64)
+	for edge, w := range p.NamedEdgeMap.Weight {
+		if _, ok := weights[edge.CallerName]; !ok {
+			weights[edge.CallerName] = 0
+		}
+		weights[edge.CalleeName] += w
+	}
+	return weights
+}
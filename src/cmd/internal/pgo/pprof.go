@@ -50,7 +50,7 @@ func FromPProf(r io.Reader) (*Profile, error) {
 		SampleValue: func(v []int64) int64 { return v[valueIndex] },
 	})
 
-	namedEdgeMap, totalWeight, err := createNamedEdgeMap(g)
+	namedEdgeMap, totalWeight, clamped, err := createNamedEdgeMap(g)
 	if err != nil {
 		return nil, err
 	}
@@ -60,16 +60,18 @@ func FromPProf(r io.Reader) (*Profile, error) {
 	}
 
 	return &Profile{
-		TotalWeight:  totalWeight,
-		NamedEdgeMap: namedEdgeMap,
+		TotalWeight:            totalWeight,
+		NamedEdgeMap:           namedEdgeMap,
+		ClampedCallSiteOffsets: clamped,
 	}, nil
 }
 
 // createNamedEdgeMap builds a map of callsite-callee edge weights from the
 // profile-graph.
 //
-// Caller should ignore the profile if totalWeight == 0.
-func createNamedEdgeMap(g *profile.Graph) (edgeMap NamedEdgeMap, totalWeight int64, err error) {
+// Caller should ignore the profile if totalWeight == 0. clamped is the number
+// of edges whose call site offset was clamped.
+func createNamedEdgeMap(g *profile.Graph) (edgeMap NamedEdgeMap, totalWeight int64, clamped int, err error) {
 	seenStartLine := false
 
 	// Process graph and build various node and edge maps which will
@@ -79,13 +81,17 @@ func createNamedEdgeMap(g *profile.Graph) (edgeMap NamedEdgeMap, totalWeight int
 		seenStartLine = seenStartLine || n.Info.StartLine != 0
 
 		canonicalName := n.Info.Name
+		offset, wasClamped := ClampCallSiteOffset(int64(n.Info.Lineno) - int64(n.Info.StartLine))
 		// Create the key to the nodeMapKey.
 		namedEdge := NamedCallEdge{
 			CallerName:     canonicalName,
-			CallSiteOffset: n.Info.Lineno - n.Info.StartLine,
+			CallSiteOffset: offset,
 		}
 
 		for _, e := range n.Out {
+			if wasClamped {
+				clamped++
+			}
 			totalWeight += e.WeightValue()
 			namedEdge.CalleeName = e.Dest.Info.Name
 			// Create new entry or increment existing entry.
@@ -97,9 +103,10 @@ func createNamedEdgeMap(g *profile.Graph) (edgeMap NamedEdgeMap, totalWeight int
 		// TODO(prattmic): If Function.start_line is missing we could
 		// fall back to using absolute line numbers, which is better
 		// than nothing.
-		return NamedEdgeMap{}, 0, fmt.Errorf("profile missing Function.start_line data (Go version of profiled application too old? Go 1.20+ automatically adds this to profiles)")
+		return NamedEdgeMap{}, 0, 0, fmt.Errorf("profile missing Function.start_line data (Go version of profiled application too old? Go 1.20+ automatically adds this to profiles)")
 	}
-	return postProcessNamedEdgeMap(weight, totalWeight)
+	edgeMap, totalWeight, err = postProcessNamedEdgeMap(weight, totalWeight)
+	return edgeMap, totalWeight, clamped, err
 }
 
 func sortByWeight(edges []NamedCallEdge, weight map[NamedCallEdge]int64) {
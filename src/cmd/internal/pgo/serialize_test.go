@@ -95,6 +95,61 @@ func TestRoundTrip(t *testing.T) {
 	testRoundTrip(t, d)
 }
 
+func TestDeserializeClampedOffset(t *testing.T) {
+	in := serializationHeader + "a\nb\n-4294967296 1\nc\nd\n-3 2\n"
+	d, err := FromSerialized(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("FromSerialized got err %v want nil", err)
+	}
+	if d.ClampedCallSiteOffsets != 1 {
+		t.Errorf("ClampedCallSiteOffsets got %d want 1", d.ClampedCallSiteOffsets)
+	}
+	want := []NamedCallEdge{
+		{CallerName: "a", CalleeName: "b", CallSiteOffset: -MaxCallSiteOffset},
+		{CallerName: "c", CalleeName: "d", CallSiteOffset: -3},
+	}
+	if !reflect.DeepEqual(d.NamedEdgeMap.ByWeight, want) {
+		t.Errorf("ByWeight got %+v want %+v", d.NamedEdgeMap.ByWeight, want)
+	}
+}
+
+func TestValidateSerialized(t *testing.T) {
+	in := serializationHeader +
+		"a\nb\n1 10\n" +
+		"c\nd\nx 5\n" + // malformed offset
+		"a\nb\n1 3\n" + // duplicate
+		"e\nf\n2 3 4\n" + // too many fields
+		"g\nh\n3 2\n"
+	d, errs := ValidateSerialized(strings.NewReader(in))
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`line 7: preprocessed profile error processing call line: strconv.ParseInt: parsing "x": invalid syntax`,
+		`line 10: preprocessed profile contains duplicate edge {CallerName:a CalleeName:b CallSiteOffset:1}`,
+		`line 13: preprocessed profile entry got [2 3 4] want 2 fields`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateSerialized errors got %q want %q", got, want)
+	}
+	wantEdges := []NamedCallEdge{
+		{CallerName: "a", CalleeName: "b", CallSiteOffset: 1},
+		{CallerName: "g", CalleeName: "h", CallSiteOffset: 3},
+	}
+	if !reflect.DeepEqual(d.NamedEdgeMap.ByWeight, wantEdges) {
+		t.Errorf("ByWeight got %+v want %+v", d.NamedEdgeMap.ByWeight, wantEdges)
+	}
+	if d.TotalWeight != 12 {
+		t.Errorf("TotalWeight got %d want 12", d.TotalWeight)
+	}
+
+	// FromSerialized stops at the first problem.
+	if _, err := FromSerialized(strings.NewReader(in)); err == nil || err.Error() != want[0][len("line 7: "):] {
+		t.Errorf("FromSerialized got err %v want %s", err, want[0][len("line 7: "):])
+	}
+}
+
 func constructFuzzProfile(t *testing.T, b []byte) *Profile {
 	// The fuzzer can't construct an arbitrary structure, so instead we
 	// consume bytes from b to act as our edge data.
@@ -155,6 +210,11 @@ func constructFuzzProfile(t *testing.T, b []byte) *Profile {
 		if !ok {
 			break
 		}
+		if _, clamped := ClampCallSiteOffset(line); clamped {
+			// Out of range offsets don't round trip; see
+			// TestDeserializeClampedOffset.
+			t.Skip("call site offset out of range")
+		}
 
 		edge := NamedCallEdge{
 			CallerName: caller,
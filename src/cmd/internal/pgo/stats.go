@@ -0,0 +1,75 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgo
+
+// Stats summarizes a profile, to sanity-check it before use.
+type Stats struct {
+	TotalWeight int64
+	Edges       int // number of call edges
+	Funcs       int // number of functions appearing in the profile
+
+	// CDF describes the distribution of edge weight, at each of
+	// StatsPercents.
+	CDF []CDFPoint
+
+	// Top are the hottest edges, hottest first.
+	Top []WeightedCallEdge
+}
+
+// A WeightedCallEdge is a call edge with its weight.
+type WeightedCallEdge struct {
+	NamedCallEdge
+	Weight int64
+}
+
+// A CDFPoint is a point of the cumulative distribution of edge weight.
+type CDFPoint struct {
+	// Percent is a percentage of the total edge weight.
+	Percent float64
+
+	// Edges is the number of hottest edges that together make up more
+	// than Percent of the total weight, and MinWeight is the weight of the
+	// lightest of them. With a CDF threshold of Percent, as in
+	// -d=pgoinlinecdfthreshold, these are the hot edges.
+	Edges     int
+	MinWeight int64
+}
+
+// StatsPercents are the CDF percentages reported by Stats.
+var StatsPercents = []float64{50, 90, 95, 99, 99.9}
+
+// Stats returns statistics about the profile, including the top hottest
+// edges.
+func (p *Profile) Stats(top int) *Stats {
+	s := &Stats{
+		TotalWeight: p.TotalWeight,
+		Edges:       len(p.NamedEdgeMap.ByWeight),
+		Funcs:       len(p.FuncEntryWeights()),
+	}
+
+	edges := p.NamedEdgeMap.ByWeight
+	i, cum := 0, int64(0)
+	for _, pct := range StatsPercents {
+		// Include the edge that makes it go over the threshold, as in
+		// hotNodesFromCDF in the inliner.
+		for i < len(edges) && (i == 0 || WeightInPercentage(cum, p.TotalWeight) <= pct) {
+			cum += p.NamedEdgeMap.Weight[edges[i]]
+			i++
+		}
+		pt := CDFPoint{Percent: pct, Edges: i}
+		if i > 0 {
+			pt.MinWeight = p.NamedEdgeMap.Weight[edges[i-1]]
+		}
+		s.CDF = append(s.CDF, pt)
+	}
+
+	if top < len(edges) {
+		edges = edges[:top]
+	}
+	for _, e := range edges {
+		s.Top = append(s.Top, WeightedCallEdge{e, p.NamedEdgeMap.Weight[e]})
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	p := emptyProfile()
+	add := func(caller, callee string, offset int, w int64) {
+		e := NamedCallEdge{CallerName: caller, CalleeName: callee, CallSiteOffset: offset}
+		p.NamedEdgeMap.Weight[e] += w
+		p.NamedEdgeMap.ByWeight = append(p.NamedEdgeMap.ByWeight, e)
+		p.TotalWeight += w
+	}
+	// Added in decreasing weight order, as ByWeight requires.
+	add("main", "a", 1, 600)
+	add("a", "b", 2, 300)
+	add("main", "c", 3, 90)
+	add("c", "b", 4, 9)
+	add("b", "d", 5, 1)
+
+	s := p.Stats(2)
+	want := &Stats{
+		TotalWeight: 1000,
+		Edges:       5,
+		Funcs:       5,
+		CDF: []CDFPoint{
+			{Percent: 50, Edges: 1, MinWeight: 600},
+			{Percent: 90, Edges: 3, MinWeight: 90},
+			{Percent: 95, Edges: 3, MinWeight: 90},
+			{Percent: 99, Edges: 4, MinWeight: 9},
+			{Percent: 99.9, Edges: 5, MinWeight: 1},
+		},
+		Top: []WeightedCallEdge{
+			{NamedCallEdge{CallerName: "main", CalleeName: "a", CallSiteOffset: 1}, 600},
+			{NamedCallEdge{CallerName: "a", CalleeName: "b", CallSiteOffset: 2}, 300},
+		},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("Stats got %+v want %+v", s, want)
+	}
+}
@@ -124,10 +124,10 @@ func trampoline(ctxt *Link, s loader.Sym) {
 
 		if ldr.SymValue(rs) == 0 && ldr.SymType(rs) != sym.SDYNIMPORT && ldr.SymType(rs) != sym.SUNDEFEXT {
 			// Symbols in the same package are laid out together (if we
-			// don't randomize the function order).
+			// don't randomize or otherwise reorder the functions).
 			// Except that if SymPkg(s) == "", it is a host object symbol
 			// which may call an external symbol via PLT.
-			if ldr.SymPkg(s) != "" && ldr.SymPkg(rs) == ldr.SymPkg(s) && *flagRandLayout == 0 {
+			if ldr.SymPkg(s) != "" && ldr.SymPkg(rs) == ldr.SymPkg(s) && *flagRandLayout == 0 && !ctxt.textReordered {
 				// RISC-V is only able to reach +/-1MiB via a JAL instruction.
 				// We need to generate a trampoline when an address is
 				// currently unknown.
@@ -136,7 +136,7 @@ func trampoline(ctxt *Link, s loader.Sym) {
 				}
 			}
 			// Runtime packages are laid out together.
-			if isRuntimeDepPkg(ldr.SymPkg(s)) && isRuntimeDepPkg(ldr.SymPkg(rs)) && *flagRandLayout == 0 {
+			if isRuntimeDepPkg(ldr.SymPkg(s)) && isRuntimeDepPkg(ldr.SymPkg(rs)) && *flagRandLayout == 0 && !ctxt.textReordered {
 				continue
 			}
 		}
@@ -2419,6 +2419,11 @@ func (ctxt *Link) textaddress() {
 			textp[i], textp[j] = textp[j], textp[i]
 		})
 	}
+	if *flagSymbolOrder != "" {
+		ctxt.symbolOrderTextLayout()
+	} else {
+		ctxt.hotColdTextLayout(sect)
+	}
 
 	text := ctxt.xdefine("runtime.text", sym.STEXT, 0)
 	etext := ctxt.xdefine("runtime.etext", sym.STEXT, 0)
@@ -2557,6 +2562,7 @@ func (ctxt *Link) textaddress() {
 		ldr.SetSymValue(etext, int64(va))
 		ldr.SetSymValue(text, int64(Segtext.Sections[0].Vaddr))
 	}
+	ctxt.defineTextLayoutMarkers()
 }
 
 // assigns address for a text symbol, returns (possibly new) section, its number, and the address.
@@ -2575,10 +2581,19 @@ func assignAddress(ctxt *Link, sect *sym.Section, n int, s loader.Sym, va uint64
 	if align == 0 {
 		align = int32(Funcalign)
 	}
+	if align < int32(*flagFuncAlign) {
+		// -funcalign also raises explicitly set alignments.
+		align = int32(*flagFuncAlign)
+	}
 	va = uint64(Rnd(int64(va), int64(align)))
 	if sect.Align < align {
 		sect.Align = align
 	}
+	if ctxt.hotTextAlign != 0 && (s == ctxt.hotTextStart || s == ctxt.hotTextNext) {
+		// Pad to the absolute address rather than raising the
+		// section alignment, which would move the section.
+		va = uint64(Rnd(int64(va), ctxt.hotTextAlign))
+	}
 
 	funcsize := uint64(abi.MINFUNC) // spacing required for findfunctab
 	if ldr.SymSize(s) > abi.MINFUNC {
@@ -2604,7 +2619,7 @@ func assignAddress(ctxt *Link, sect *sym.Section, n int, s loader.Sym, va uint64
 		}
 
 		if va-sect.Vaddr+funcsize+maxSizeTrampolines(ctxt, ldr, s, isTramp) > textSizelimit {
-			sectAlign := int32(thearch.Funcalign)
+			sectAlign := int32(Funcalign)
 			if ctxt.IsPPC64() {
 				// Align the next text section to the worst case function alignment likely
 				// to be encountered when processing function symbols. The start address
@@ -2613,9 +2628,11 @@ func assignAddress(ctxt *Link, sect *sym.Section, n int, s loader.Sym, va uint64
 				// larger than Funcalign, or usage of ISA 3.1 prefixed instructions
 				// (see ISA 3.1 Book I 1.9).
 				const ppc64maxFuncalign = 64
-				sectAlign = ppc64maxFuncalign
-				va = uint64(Rnd(int64(va), ppc64maxFuncalign))
+				if sectAlign < ppc64maxFuncalign {
+					sectAlign = ppc64maxFuncalign
+				}
 			}
+			va = uint64(Rnd(int64(va), int64(sectAlign)))
 
 			// Set the length for the previous text section
 			sect.Length = va - sect.Vaddr
@@ -1214,6 +1214,63 @@ func TestPackageNameAttr(t *testing.T) {
 	}
 }
 
+func TestPGOHotnessAttr(t *testing.T) {
+	const dwarfAttrGoHotness = dwarf.Attr(0x2908)
+
+	testenv.MustHaveGoBuild(t)
+
+	mustHaveDWARF(t)
+
+	t.Parallel()
+
+	const prog = `
+package main
+
+//go:noinline
+func hot() int { return 1 }
+
+//go:noinline
+func warm() int { return 2 }
+
+//go:noinline
+func cold() int { return 3 }
+
+func main() {
+	if hot()+warm() == 0 {
+		println(cold())
+	}
+}
+`
+	const profile = `GO PREPROFILE V1
+main.main
+main.hot
+1 100
+main.main
+main.warm
+1 1
+`
+	prof := filepath.Join(t.TempDir(), "prof.pgo")
+	if err := os.WriteFile(prof, []byte(profile), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	_, ex := gobuildAndExamine(t, prog, "-gcflags=-pgoprofile="+prof+" -d=pgodwarf=1")
+	for _, tc := range []struct {
+		sym  string
+		want int64
+	}{
+		{"main.hot", 1},
+		{"main.warm", 0},
+		{"main.cold", 2},
+	} {
+		die := findSubprogramDIE(t, ex, tc.sym)
+		got, _ := die.Val(dwarfAttrGoHotness).(int64)
+		if got != tc.want {
+			t.Errorf("%s: DW_AT_go_hotness is %d, want %d", tc.sym, got, tc.want)
+		}
+	}
+}
+
 func TestMachoIssue32233(t *testing.T) {
 	testenv.MustHaveGoBuild(t)
 	testenv.MustHaveCGO(t)
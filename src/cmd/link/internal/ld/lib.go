@@ -377,6 +377,9 @@ func mayberemoveoutfile() {
 
 func libinit(ctxt *Link) {
 	Funcalign = thearch.Funcalign
+	if *flagFuncAlign > Funcalign {
+		Funcalign = *flagFuncAlign
+	}
 
 	// add goroot to the end of the libdir list.
 	suffix := ""
@@ -77,6 +77,13 @@ type Link struct {
 
 	tramps []loader.Sym // trampolines
 
+	textReordered bool // text is no longer laid out in package order
+
+	// Hot text is aligned to hotTextAlign by padding before hotTextStart,
+	// its first symbol, and hotTextNext, the first symbol after it.
+	hotTextAlign              int64
+	hotTextStart, hotTextNext loader.Sym
+
 	compUnits []*sym.CompilationUnit // DWARF compilation units
 	runtimeCU *sym.CompilationUnit   // One of the runtime CUs, the last one seen.
 
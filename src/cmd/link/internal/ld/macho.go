@@ -923,6 +923,9 @@ func collectmachosyms(ctxt *Link) {
 				addsym(s)
 			}
 		}
+		for _, s := range textLayoutMarkerSyms(ldr) {
+			addsym(s)
+		}
 	}
 
 	// Add text symbols.
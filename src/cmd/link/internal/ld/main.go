@@ -106,6 +106,11 @@ var (
 	flagEntrySymbol   = flag.String("E", "", "set `entry` symbol name")
 	flagPruneWeakMap  = flag.Bool("pruneweakmap", true, "prune weak mapinit refs")
 	flagRandLayout    = flag.Int64("randlayout", 0, "randomize function layout")
+	flagHotColdText   = flag.Bool("hotcoldtext", false, "group profile-hot and never-executed functions at the start and end of text")
+	flagFuncAlign     = flag.Int("funcalign", 0, "set the minimum function alignment to `n` bytes")
+	flagHotTextAlign  = flag.Int64("hottextalign", 0, "align profile-hot text spanning at least `n` bytes to n-byte boundaries, for huge page mapping")
+	flagSymbolOrder   = flag.String("symbolorderfile", "", "lay out text symbols listed in `file` first, in the listed order")
+	flagColdReport    = flag.String("coldreport", "", "write the reachable functions that never appear in the PGO profile, with their sizes, to `file`")
 	cpuprofile        = flag.String("cpuprofile", "", "write cpu profile to `file`")
 	memprofile        = flag.String("memprofile", "", "write memory profile to `file`")
 	memprofilerate    = flag.Int64("memprofilerate", 0, "set runtime.MemProfileRate to `rate`")
@@ -249,6 +254,23 @@ func Main(arch *sys.Arch, theArch Arch) {
 	if *FlagRound != -1 && (*FlagRound < 4096 || !isPowerOfTwo(*FlagRound)) {
 		Exitf("invalid -R value 0x%x", *FlagRound)
 	}
+	if *flagFuncAlign != 0 && !isPowerOfTwo(int64(*flagFuncAlign)) {
+		Exitf("invalid -funcalign value %d", *flagFuncAlign)
+	}
+	if *flagHotTextAlign != 0 && !isPowerOfTwo(*flagHotTextAlign) {
+		Exitf("invalid -hottextalign value %d", *flagHotTextAlign)
+	}
+	if *flagHotTextAlign != 0 && !*flagHotColdText {
+		Exitf("-hottextalign requires -hotcoldtext")
+	}
+	if *flagSymbolOrder != "" && *flagRandLayout != 0 {
+		Exitf("-symbolorderfile and -randlayout cannot be used together")
+	}
+	if *flagSymbolOrder != "" && *flagHotColdText {
+		// The symbol order replaces the hot/cold layout, which would
+		// leave the hot and unlikely text markers undefined.
+		Exitf("-symbolorderfile and -hotcoldtext cannot be used together")
+	}
 
 	checkStrictDups = *FlagStrictDups
 
@@ -410,6 +432,10 @@ func Main(arch *sys.Arch, theArch Arch) {
 
 	bench.Start("textaddress")
 	ctxt.textaddress()
+	if *flagColdReport != "" {
+		bench.Start("coldReport")
+		ctxt.writeColdReport(*flagColdReport)
+	}
 	bench.Start("typelink")
 	ctxt.typelink()
 	bench.Start("buildinfo")
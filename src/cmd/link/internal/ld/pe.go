@@ -833,6 +833,9 @@ func (f *peFile) writeSymbols(ctxt *Link) {
 	if ldr.SymType(s) == sym.STEXT {
 		addsym(s)
 	}
+	for _, s := range textLayoutMarkerSyms(ldr) {
+		addsym(s)
+	}
 
 	// Add text symbols.
 	for _, s := range ctxt.Textp {
@@ -219,6 +219,11 @@ func genelfsym(ctxt *Link, elfbind elf.SymBind) {
 		putelfsym(ctxt, s, elf.STT_FUNC, elfbind)
 	}
 
+	// Hot and unlikely text marker symbols.
+	for _, s := range textLayoutMarkerSyms(ldr) {
+		putelfsym(ctxt, s, elf.STT_FUNC, elfbind)
+	}
+
 	shouldBeInSymbolTable := func(s loader.Sym) bool {
 		if ldr.AttrNotInSymbolTable(s) {
 			return false
@@ -681,6 +686,14 @@ func (ctxt *Link) symtab(pcln *pclntab) []sym.SymKind {
 	// pointers to specific parts of the module
 	moduledata.AddAddr(ctxt.Arch, ldr.Lookup("runtime.text", 0))
 	moduledata.AddAddr(ctxt.Arch, ldr.Lookup("runtime.etext", 0))
+	// The hot text, if grouped by hotColdTextLayout
+	if s := ldr.Lookup(textHotSym, 0); s != 0 && ldr.SymType(s) == sym.STEXT {
+		moduledata.AddAddr(ctxt.Arch, s)
+		moduledata.AddAddr(ctxt.Arch, ldr.Lookup(etextHotSym, 0))
+	} else {
+		moduledata.AddUint(ctxt.Arch, 0)
+		moduledata.AddUint(ctxt.Arch, 0)
+	}
 	moduledata.AddAddr(ctxt.Arch, ldr.Lookup("runtime.noptrdata", 0))
 	moduledata.AddAddr(ctxt.Arch, ldr.Lookup("runtime.enoptrdata", 0))
 	moduledata.AddAddr(ctxt.Arch, ldr.Lookup("runtime.data", 0))
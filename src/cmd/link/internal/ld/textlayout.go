@@ -0,0 +1,309 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"bufio"
+	"cmd/internal/objabi"
+	"cmd/link/internal/loader"
+	"cmd/link/internal/sym"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Profile-guided text layout.
+//
+// When compiling with a PGO profile, the compiler marks functions that are
+// hot by profile entry weight (AttrHot) and functions that never appear in
+// the profile (AttrCold). The linker groups hot functions at the start of
+// the text and cold functions at the end, keeping the original relative
+// order within each group, so that the hot code occupies as few pages as
+// possible and can be kept resident.
+//
+// The groups play the role of the .text.hot and .text.unlikely sections of
+// C toolchains. They are kept inside the single .text section, as the
+// runtime expects all text sections to be named .text, and are delimited
+// by marker symbols that are emitted into the ELF, Mach-O and PE symbol
+// tables alongside runtime.text and runtime.etext.
+const (
+	textHotSym       = "runtime.texthot"
+	etextHotSym      = "runtime.etexthot"
+	textUnlikelySym  = "runtime.textunlikely"
+	etextUnlikelySym = "runtime.etextunlikely"
+)
+
+// textLayoutMarkers lists the marker symbols in address order.
+var textLayoutMarkers = [...]string{textHotSym, etextHotSym, textUnlikelySym, etextUnlikelySym}
+
+// movableTextp returns the part of ctxt.Textp whose symbols may be
+// reordered.
+func (ctxt *Link) movableTextp() []loader.Sym {
+	ldr := ctxt.loader
+	textp := ctxt.Textp
+	i := 0
+	// don't move the buildid symbol
+	if len(textp) > 0 && ldr.SymName(textp[0]) == "go:buildid" {
+		i++
+	}
+	// Skip over C symbols, as functions in a (C object) section must stay together.
+	for i < len(textp) && (ldr.SubSym(textp[i]) != 0 || ldr.AttrSubSymbol(textp[i])) {
+		i++
+	}
+	return textp[i:]
+}
+
+// hotColdTextLayout moves the functions marked hot to the start of
+// ctxt.Textp and the functions marked cold to the end, and defines the
+// marker symbols delimiting them.
+func (ctxt *Link) hotColdTextLayout(sect *sym.Section) {
+	if !*flagHotColdText || *flagRandLayout != 0 {
+		return
+	}
+	ldr := ctxt.loader
+	textp := ctxt.movableTextp()
+
+	var hot, normal, cold []loader.Sym
+	for _, s := range textp {
+		switch {
+		case ldr.IsHot(s):
+			hot = append(hot, s)
+		case ldr.IsCold(s):
+			cold = append(cold, s)
+		default:
+			normal = append(normal, s)
+		}
+	}
+	if len(hot) == 0 && len(cold) == 0 {
+		return
+	}
+	n := copy(textp, hot)
+	n += copy(textp[n:], normal)
+	copy(textp[n:], cold)
+	ctxt.alignHotText(hot, textp[len(hot):])
+
+	for _, name := range textLayoutMarkers {
+		s := ctxt.xdefine(name, sym.STEXT, 0)
+		ldr.SetSymSect(s, sect)
+	}
+
+	ctxt.textReordered = true
+	ctxt.sortUnitTextp()
+}
+
+// alignHotText arranges for the hot text to start and end at
+// -hottextalign boundaries, so that the runtime can ask the OS to back it
+// with huge pages (see hugePageText in the runtime). rest are the symbols
+// that follow the hot text.
+//
+// This is only done if the hot text spans at least one alignment unit, as
+// the padding can take up to two units, and only for internally linked,
+// non-PIE Linux executables, where text addresses are final at link time.
+func (ctxt *Link) alignHotText(hot, rest []loader.Sym) {
+	align := *flagHotTextAlign
+	if align == 0 {
+		return
+	}
+	if len(hot) == 0 || ctxt.HeadType != objabi.Hlinux || !ctxt.IsInternal() || ctxt.BuildMode != BuildModeExe {
+		if ctxt.Debugvlog != 0 {
+			ctxt.Logf("hot text not aligned: only done for internally linked non-PIE Linux executables with hot text\n")
+		}
+		return
+	}
+	ldr := ctxt.loader
+	var size int64
+	for _, s := range hot {
+		size += ldr.SymSize(s)
+	}
+	if size < align {
+		if ctxt.Debugvlog != 0 {
+			ctxt.Logf("hot text not aligned: size %d smaller than -hottextalign %d\n", size, align)
+		}
+		return
+	}
+	ctxt.hotTextAlign = align
+	ctxt.hotTextStart = hot[0]
+	if len(rest) > 0 {
+		ctxt.hotTextNext = rest[0]
+	}
+}
+
+// symbolOrderTextLayout lays out the text symbols named in the file given
+// by -symbolorderfile first, in the order they are listed, followed by the
+// remaining symbols in their original order. This lets external tools
+// drive function placement without recompiling.
+//
+// The file lists one symbol name per line. Blank lines and lines starting
+// with '#' are ignored. Names that do not match a text symbol are ignored,
+// and reported with -v. A name that matches several symbols, such as the
+// ABI wrapper and the implementation of a function, places all of them.
+func (ctxt *Link) symbolOrderTextLayout() {
+	ldr := ctxt.loader
+	f, err := os.Open(*flagSymbolOrder)
+	if err != nil {
+		Exitf("cannot open symbol ordering file: %v", err)
+	}
+	defer f.Close()
+
+	textp := ctxt.movableTextp()
+	byName := make(map[string][]loader.Sym)
+	for _, s := range textp {
+		name := ldr.SymName(s)
+		byName[name] = append(byName[name], s)
+	}
+
+	placed := make(map[loader.Sym]bool)
+	ordered := make([]loader.Sym, 0, len(textp))
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		syms, ok := byName[name]
+		if !ok {
+			if ctxt.Debugvlog != 0 {
+				ctxt.Logf("symbol ordering file: no text symbol %s\n", name)
+			}
+			continue
+		}
+		for _, s := range syms {
+			if !placed[s] {
+				placed[s] = true
+				ordered = append(ordered, s)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		Exitf("reading symbol ordering file: %v", err)
+	}
+	if len(ordered) == 0 {
+		return
+	}
+	for _, s := range textp {
+		if !placed[s] {
+			ordered = append(ordered, s)
+		}
+	}
+	copy(textp, ordered)
+
+	ctxt.textReordered = true
+	ctxt.sortUnitTextp()
+}
+
+// sortUnitTextp reorders the Textp lists of the compilation units to match
+// the order of ctxt.Textp, so that the first symbol of each unit, which is
+// used as the base address for DWARF ranges, is still its lowest address.
+func (ctxt *Link) sortUnitTextp() {
+	pos := make(map[sym.LoaderSym]int, len(ctxt.Textp))
+	for i, s := range ctxt.Textp {
+		pos[sym.LoaderSym(s)] = i
+	}
+	for _, lib := range ctxt.Library {
+		for _, unit := range lib.Units {
+			sort.SliceStable(unit.Textp, func(i, j int) bool {
+				return pos[unit.Textp[i]] < pos[unit.Textp[j]]
+			})
+		}
+	}
+}
+
+// defineTextLayoutMarkers sets the addresses of the marker symbols defined
+// by hotColdTextLayout, once text addresses are assigned. A marker pair
+// delimiting an empty group is placed at the boundary with the default
+// group.
+func (ctxt *Link) defineTextLayoutMarkers() {
+	ldr := ctxt.loader
+	if ldr.Lookup(textHotSym, 0) == 0 {
+		return
+	}
+
+	var hotStart, hotEnd, coldStart, coldEnd int64 = -1, -1, -1, -1
+	for _, s := range ctxt.Textp {
+		start := ldr.SymValue(s)
+		end := start + ldr.SymSize(s)
+		switch {
+		case ldr.IsHot(s):
+			if hotStart < 0 {
+				hotStart = start
+			}
+			hotEnd = end
+		case ldr.IsCold(s):
+			if coldStart < 0 {
+				coldStart = start
+			}
+			coldEnd = end
+		}
+	}
+	etext := ldr.SymValue(ldr.Lookup("runtime.etext", 0))
+	if hotStart < 0 {
+		hotStart = ldr.SymValue(ldr.Lookup("runtime.text", 0))
+		hotEnd = hotStart
+	}
+	if coldStart < 0 {
+		coldStart = etext
+		coldEnd = etext
+	}
+	ldr.SetSymValue(ldr.Lookup(textHotSym, 0), hotStart)
+	ldr.SetSymValue(ldr.Lookup(etextHotSym, 0), hotEnd)
+	ldr.SetSymValue(ldr.Lookup(textUnlikelySym, 0), coldStart)
+	ldr.SetSymValue(ldr.Lookup(etextUnlikelySym, 0), coldEnd)
+}
+
+// textLayoutMarkerSyms returns the defined marker symbols, for inclusion in
+// the output symbol table.
+func textLayoutMarkerSyms(ldr *loader.Loader) []loader.Sym {
+	var syms []loader.Sym
+	for _, name := range textLayoutMarkers {
+		if s := ldr.Lookup(name, 0); s != 0 && ldr.SymType(s) == sym.STEXT {
+			syms = append(syms, s)
+		}
+	}
+	return syms
+}
+
+// writeColdReport writes to file the reachable text symbols that the
+// compiler marked cold, that is, functions that were compiled with a PGO
+// profile but never appear in it, largest first. Such functions may be
+// candidates for deletion or for moving behind a build tag. The report is
+// an analysis only: the code is linked as usual.
+//
+// Each line holds the size in bytes and the name of a symbol, and a final
+// comment line gives the total size.
+func (ctxt *Link) writeColdReport(file string) {
+	ldr := ctxt.loader
+	var cold []loader.Sym
+	var total int64
+	for _, s := range ctxt.Textp {
+		if ldr.IsCold(s) {
+			cold = append(cold, s)
+			total += ldr.SymSize(s)
+		}
+	}
+	sort.SliceStable(cold, func(i, j int) bool {
+		si, sj := ldr.SymSize(cold[i]), ldr.SymSize(cold[j])
+		if si != sj {
+			return si > sj
+		}
+		return ldr.SymName(cold[i]) < ldr.SymName(cold[j])
+	})
+
+	f, err := os.Create(file)
+	if err != nil {
+		Exitf("cannot create cold function report: %v", err)
+	}
+	w := bufio.NewWriter(f)
+	for _, s := range cold {
+		fmt.Fprintf(w, "%d\t%s\n", ldr.SymSize(s), ldr.SymName(s))
+	}
+	fmt.Fprintf(w, "# %d never-executed functions, %d bytes\n", len(cold), total)
+	if err := w.Flush(); err != nil {
+		Exitf("writing cold function report: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		Exitf("writing cold function report: %v", err)
+	}
+}
@@ -1190,6 +1190,30 @@ func (l *Loader) IsPkgInit(i Sym) bool {
 	return r.Sym(li).IsPkgInit()
 }
 
+// Returns whether this function symbol is marked hot by the profile.
+func (l *Loader) IsHot(i Sym) bool {
+	return l.textClass(i) == goobj.TextClassHot
+}
+
+// Returns whether this function symbol is marked cold (never executed)
+// by the profile.
+func (l *Loader) IsCold(i Sym) bool {
+	return l.textClass(i) == goobj.TextClassUnlikely
+}
+
+// textClass returns the placement of function symbol i in the text
+// recorded in its FuncInfo by the compiler.
+func (l *Loader) textClass(i Sym) uint8 {
+	if l.IsExternal(i) {
+		return goobj.TextClassDefault
+	}
+	fi := l.FuncInfo(i)
+	if !fi.Valid() {
+		return goobj.TextClassDefault
+	}
+	return fi.TextClass()
+}
+
 // Return whether this is a trampoline of a deferreturn call.
 func (l *Loader) IsDeferReturnTramp(i Sym) bool {
 	return l.deferReturnTramp[i]
@@ -2020,6 +2044,10 @@ func (fi *FuncInfo) FuncFlag() abi.FuncFlag {
 	return (*goobj.FuncInfo)(nil).ReadFuncFlag(fi.data)
 }
 
+func (fi *FuncInfo) TextClass() uint8 {
+	return (*goobj.FuncInfo)(nil).ReadTextClass(fi.data)
+}
+
 func (fi *FuncInfo) StartLine() int32 {
 	return (*goobj.FuncInfo)(nil).ReadStartLine(fi.data)
 }
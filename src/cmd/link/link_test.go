@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"debug/macho"
 	"errors"
+	"fmt"
 	"internal/platform"
 	"internal/testenv"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -573,6 +575,18 @@ func main() {
 `
 
 var testFuncAlignAsmSources = map[string]string{
+	"amd64": `
+#include "textflag.h"
+
+TEXT	·alignPc(SB),NOSPLIT, $0-0
+	MOVQ	$2, AX
+	PCALIGN	$512
+	MOVQ	$3, BX
+	RET
+
+GLOBL	·alignPcFnAddr(SB),RODATA,$8
+DATA	·alignPcFnAddr(SB)/8,$·alignPc(SB)
+`,
 	"arm64": `
 #include "textflag.h"
 
@@ -600,11 +614,11 @@ DATA	·alignPcFnAddr(SB)/8,$·alignPc(SB)
 }
 
 // TestFuncAlign verifies that the address of a function can be aligned
-// with a specific value on arm64 and loong64.
+// with a specific value on amd64, arm64 and loong64.
 func TestFuncAlign(t *testing.T) {
 	testFuncAlignAsmSrc := testFuncAlignAsmSources[runtime.GOARCH]
 	if len(testFuncAlignAsmSrc) == 0 || runtime.GOOS != "linux" {
-		t.Skip("skipping on non-linux/{arm64,loong64} platform")
+		t.Skip("skipping on non-linux/{amd64,arm64,loong64} platform")
 	}
 	testenv.MustHaveGoBuild(t)
 
@@ -645,6 +659,62 @@ func TestFuncAlign(t *testing.T) {
 	}
 }
 
+func TestFuncAlignFlag(t *testing.T) {
+	// Test that -funcalign sets the minimum alignment of all functions.
+	testenv.MustHaveGoBuild(t)
+
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "hello.go")
+	err := os.WriteFile(src, []byte("package main\n\nfunc main() { println(\"hello\") }\n"), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arches := []string{"amd64", "arm64", "riscv64", "ppc64le"}
+	if testing.Short() && os.Getenv("GO_BUILDER_NAME") == "" {
+		// Avoid building the standard library for other architectures.
+		arches = []string{runtime.GOARCH}
+	}
+	const align = 128
+	for _, arch := range arches {
+		t.Run(arch, func(t *testing.T) {
+			exe := filepath.Join(tmpdir, "hello-"+arch)
+			cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags=-funcalign="+strconv.Itoa(align), "-o", exe, src)
+			cmd.Env = append(os.Environ(), "GOARCH="+arch, "GOOS=linux")
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("build failed: %v\n%s", err, out)
+			}
+
+			cmd = testenv.Command(t, testenv.GoToolPath(t), "tool", "nm", exe)
+			out, err = cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("go tool nm failed: %v\n%s", err, out)
+			}
+			n := 0
+			for _, line := range strings.Split(string(out), "\n") {
+				f := strings.Fields(line)
+				if len(f) < 3 || f[1] != "T" {
+					continue
+				}
+				addr, err := strconv.ParseUint(f[0], 16, 64)
+				if err != nil {
+					continue
+				}
+				n++
+				if addr%align != 0 {
+					t.Errorf("function %s at %#x not aligned to %d", f[2], addr, align)
+				}
+			}
+			if n == 0 {
+				t.Fatalf("no functions found in nm output:\n%s", out)
+			}
+		})
+	}
+}
+
 const testTrampSrc = `
 package main
 import "fmt"
@@ -1416,6 +1486,328 @@ func TestRandLayout(t *testing.T) {
 	}
 }
 
+const testHotColdTextSrc = `
+package main
+
+//go:noinline
+func hot() int { return 1 }
+
+//go:noinline
+func warm() int { return 2 }
+
+//go:noinline
+func cold() int { return 3 }
+
+func main() {
+	if hot()+warm() == 0 {
+		println(cold())
+	}
+}
+`
+
+const testHotColdTextProfile = `GO PREPROFILE V1
+main.main
+main.hot
+1 100
+main.main
+main.warm
+1 1
+`
+
+func TestHotColdTextLayout(t *testing.T) {
+	// Test that functions marked hot and cold by the profile are placed
+	// in the hot and unlikely text ranges.
+	testenv.MustHaveGoBuild(t)
+
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+
+	src := filepath.Join(tmpdir, "hotcold.go")
+	err := os.WriteFile(src, []byte(testHotColdTextSrc), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(tmpdir, "hotcold.pgo")
+	err = os.WriteFile(prof, []byte(testHotColdTextProfile), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exe := filepath.Join(tmpdir, "hotcold.exe")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-gcflags=-pgoprofile="+prof+" -d=pgotextlayout=1", "-ldflags=-hotcoldtext", "-o", exe, src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	cmd = testenv.Command(t, exe)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("executable failed to run: %v\n%s", err, out)
+	}
+
+	addrs := nmAddrs(t, exe, "main.hot", "main.warm", "main.cold", "runtime.texthot", "runtime.etexthot", "runtime.textunlikely", "runtime.etextunlikely")
+
+	in := func(s, start, end string) bool {
+		return addrs[start] <= addrs[s] && addrs[s] < addrs[end]
+	}
+	if !in("main.hot", "runtime.texthot", "runtime.etexthot") {
+		t.Errorf("main.hot at %#x not in hot text [%#x, %#x)", addrs["main.hot"], addrs["runtime.texthot"], addrs["runtime.etexthot"])
+	}
+	if !in("main.cold", "runtime.textunlikely", "runtime.etextunlikely") {
+		t.Errorf("main.cold at %#x not in unlikely text [%#x, %#x)", addrs["main.cold"], addrs["runtime.textunlikely"], addrs["runtime.etextunlikely"])
+	}
+	if in("main.warm", "runtime.texthot", "runtime.etexthot") || in("main.warm", "runtime.textunlikely", "runtime.etextunlikely") {
+		t.Errorf("main.warm at %#x unexpectedly in hot or unlikely text", addrs["main.warm"])
+	}
+}
+
+func TestColdReport(t *testing.T) {
+	// Test that -coldreport lists the functions that never appear in
+	// the profile, and not the ones that do.
+	testenv.MustHaveGoBuild(t)
+
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+
+	src := filepath.Join(tmpdir, "coldreport.go")
+	err := os.WriteFile(src, []byte(testHotColdTextSrc), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(tmpdir, "coldreport.pgo")
+	err = os.WriteFile(prof, []byte(testHotColdTextProfile), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := filepath.Join(tmpdir, "cold.txt")
+	exe := filepath.Join(tmpdir, "coldreport.exe")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-gcflags=-pgoprofile="+prof+" -d=pgotextlayout=1", "-ldflags=-coldreport="+report, "-o", exe, src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcs := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		size, name, ok := strings.Cut(line, "\t")
+		if _, err := strconv.Atoi(size); !ok || err != nil {
+			t.Fatalf("malformed report line %q", line)
+		}
+		funcs[name] = true
+	}
+	if !funcs["main.cold"] {
+		t.Errorf("main.cold missing from report:\n%s", data)
+	}
+	for _, name := range []string{"main.main", "main.hot", "main.warm"} {
+		if funcs[name] {
+			t.Errorf("%s unexpectedly in report:\n%s", name, data)
+		}
+	}
+}
+
+func TestPGOFuncAlign(t *testing.T) {
+	// Test that -d=pgofuncalign aligns the functions marked hot by the
+	// profile, and leaves cold functions with the default alignment.
+	testenv.MustHaveGoBuild(t)
+
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+
+	src := filepath.Join(tmpdir, "funcalign.go")
+	err := os.WriteFile(src, []byte(testHotColdTextSrc), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(tmpdir, "funcalign.pgo")
+	err = os.WriteFile(prof, []byte(testHotColdTextProfile), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const align = 256
+	exe := filepath.Join(tmpdir, "funcalign.exe")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-gcflags=-pgoprofile="+prof+" -d=pgofuncalign="+strconv.Itoa(align), "-o", exe, src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	cmd = testenv.Command(t, exe)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("executable failed to run: %v\n%s", err, out)
+	}
+
+	addrs := nmAddrs(t, exe, "main.hot", "main.cold")
+	if addr := addrs["main.hot"]; addr%align != 0 {
+		t.Errorf("main.hot at %#x not aligned to %d", addr, align)
+	}
+	if runtime.GOARCH == "amd64" {
+		// The jump padding of the x86 assembler assumes 32-byte
+		// aligned functions.
+		if addr := addrs["main.cold"]; addr%32 != 0 {
+			t.Errorf("main.cold at %#x not aligned to 32", addr)
+		}
+	}
+}
+
+func TestHotTextAlign(t *testing.T) {
+	// Test that -hottextalign pads the hot text to start and end at
+	// aligned addresses, and that the program runs.
+	testenv.MustHaveGoBuild(t)
+	if runtime.GOOS != "linux" {
+		t.Skip("hot text alignment is only supported on Linux")
+	}
+
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+
+	// A hot function large enough to span the alignment.
+	var buf bytes.Buffer
+	buf.WriteString("package main\n\n//go:noinline\nfunc g(int) {}\n\n//go:noinline\nfunc hot() {\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&buf, "\tg(%d)\n", i)
+	}
+	buf.WriteString("}\n\n//go:noinline\nfunc warm() {}\n\nfunc main() {\n\thot()\n\twarm()\n}\n")
+	src := filepath.Join(tmpdir, "hotalign.go")
+	err := os.WriteFile(src, buf.Bytes(), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(tmpdir, "hotalign.pgo")
+	err = os.WriteFile(prof, []byte(testHotColdTextProfile), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const align = 1024
+	exe := filepath.Join(tmpdir, "hotalign.exe")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-gcflags=-pgoprofile="+prof+" -d=pgotextlayout=1", "-ldflags=-hotcoldtext -hottextalign="+strconv.Itoa(align), "-o", exe, src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	// Also ask the runtime to advise huge pages for the hot text.
+	cmd = testenv.Command(t, exe)
+	cmd.Env = append(os.Environ(), "GODEBUG=hugepagetext=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("executable failed to run: %v\n%s", err, out)
+	}
+
+	addrs := nmAddrs(t, exe, "main.hot", "runtime.texthot", "runtime.etexthot")
+	if start := addrs["runtime.texthot"]; start%align != 0 || addrs["main.hot"] != start {
+		t.Errorf("hot text starts at %#x, main.hot at %#x, want both at the same %#x-aligned address", start, addrs["main.hot"], align)
+	}
+	if size := addrs["runtime.etexthot"] - addrs["runtime.texthot"]; size < align {
+		t.Fatalf("hot text size %#x smaller than alignment %#x", size, align)
+	}
+	// The first function after the hot text is aligned too.
+	end := addrs["runtime.etexthot"]
+	next := ^uint64(0)
+	for name, addr := range addrs {
+		if addr >= end && addr < next && !strings.HasPrefix(name, "runtime.etext") && !strings.HasPrefix(name, "runtime.textunlikely") {
+			next = addr
+		}
+	}
+	if next%align != 0 {
+		t.Errorf("first symbol after hot text at %#x, want %#x-aligned", next, align)
+	}
+
+	// -hottextalign has no effect without -hotcoldtext, so it is rejected.
+	cmd = testenv.Command(t, testenv.GoToolPath(t), "build", "-gcflags=-pgoprofile="+prof+" -d=pgotextlayout=1", "-ldflags=-hottextalign="+strconv.Itoa(align), "-o", exe, src)
+	out, err = cmd.CombinedOutput()
+	if err == nil || !bytes.Contains(out, []byte("-hottextalign requires -hotcoldtext")) {
+		t.Errorf("build with -hottextalign and without -hotcoldtext: got err %v, output:\n%s", err, out)
+	}
+}
+
+// nmAddrs returns the addresses of the symbols of exe, as reported by
+// go tool nm. It fails the test if any of the want symbols is missing.
+func nmAddrs(t *testing.T, exe string, want ...string) map[string]uint64 {
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "tool", "nm", exe)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go tool nm failed: %v\n%s", err, out)
+	}
+	addrs := make(map[string]uint64)
+	for _, line := range strings.Split(string(out), "\n") {
+		f := strings.Fields(line)
+		if len(f) < 3 {
+			continue
+		}
+		addr, err := strconv.ParseUint(f[0], 16, 64)
+		if err != nil {
+			continue
+		}
+		addrs[f[2]] = addr
+	}
+	for _, s := range want {
+		if _, ok := addrs[s]; !ok {
+			t.Fatalf("symbol %s not found in nm output:\n%s", s, out)
+		}
+	}
+	return addrs
+}
+
+func TestSymbolOrderFile(t *testing.T) {
+	// Test that -symbolorderfile places the listed functions first, in
+	// the listed order.
+	testenv.MustHaveGoBuild(t)
+
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+
+	src := filepath.Join(tmpdir, "order.go")
+	err := os.WriteFile(src, []byte(testHotColdTextSrc), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := filepath.Join(tmpdir, "order.txt")
+	err = os.WriteFile(order, []byte("# hand-written order\nmain.cold\n\nmain.warm\nmain.nonexistent\nmain.hot\n"), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exe := filepath.Join(tmpdir, "order.exe")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags=-symbolorderfile="+order, "-o", exe, src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	cmd = testenv.Command(t, exe)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("executable failed to run: %v\n%s", err, out)
+	}
+
+	addrs := nmAddrs(t, exe, "main.hot", "main.warm", "main.cold", "main.main", "runtime.main")
+	if !(addrs["main.cold"] < addrs["main.warm"] && addrs["main.warm"] < addrs["main.hot"]) {
+		t.Errorf("functions not in listed order: main.cold %#x, main.warm %#x, main.hot %#x", addrs["main.cold"], addrs["main.warm"], addrs["main.hot"])
+	}
+	for _, s := range []string{"main.main", "runtime.main"} {
+		if addrs[s] < addrs["main.hot"] {
+			t.Errorf("unlisted %s at %#x placed before listed main.hot at %#x", s, addrs[s], addrs["main.hot"])
+		}
+	}
+
+	// The symbol order replaces the hot/cold layout, so the two
+	// cannot be combined.
+	cmd = testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags=-symbolorderfile="+order+" -hotcoldtext", "-o", exe, src)
+	out, err = cmd.CombinedOutput()
+	if err == nil || !bytes.Contains(out, []byte("-symbolorderfile and -hotcoldtext cannot be used together")) {
+		t.Errorf("build with -symbolorderfile and -hotcoldtext: got err %v, output:\n%s", err, out)
+	}
+}
+
 func TestCheckLinkname(t *testing.T) {
 	// Test that code containing blocked linknames does not build.
 	testenv.MustHaveGoBuild(t)
@@ -8,9 +8,27 @@
 //
 // Usage:
 //
-//	go tool preprofile [-v] [-o output] -i input
+//	go tool preprofile [-v] [-order] [-stats] [-o output] -i input
+//	go tool preprofile -validate [-bin binary] [-o output] -i input
 //
+// With -order, preprofile instead writes a ranked list of functions for use
+// with the linker's -symbolorderfile flag. Functions connected by hot call
+// edges are grouped into clusters, each introduced by a comment line with
+// the cluster weight, so the order can be inspected and edited by hand.
 //
+// With -stats, preprofile instead prints a summary of the profile: its total
+// weight, the number of call edges and functions, how many of the hottest
+// edges make up given percentages of the total weight, and the -top hottest
+// edges. The input may be a pprof profile or a preprocessed profile.
+//
+// With -validate, preprofile checks a preprocessed profile for malformed and
+// duplicate entries and call site offsets out of range. With -bin, it also
+// checks that the functions of the profile are in the given binary, and that
+// call site offsets are within their callers, according to the DWARF line
+// tables. If the binary has no DWARF, the symbol table is used instead, and
+// functions that were inlined at every call site are reported as missing.
+// Each problem is printed, and preprofile exits with a non-zero status if
+// there are any.
 
 package main
 
@@ -21,12 +39,15 @@ import (
 	"cmd/internal/telemetry"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"text/tabwriter"
 )
 
 func usage() {
-	fmt.Fprintf(os.Stderr, "usage: go tool preprofile [-v] [-o output] -i input\n\n")
+	fmt.Fprintf(os.Stderr, "usage: go tool preprofile [-v] [-order] [-stats] [-o output] -i input\n")
+	fmt.Fprintf(os.Stderr, "       go tool preprofile -validate [-bin binary] [-o output] -i input\n\n")
 	flag.PrintDefaults()
 	os.Exit(2)
 }
@@ -34,9 +55,15 @@ func usage() {
 var (
 	output = flag.String("o", "", "output file path")
 	input  = flag.String("i", "", "input pprof file path")
+	order  = flag.Bool("order", false, "write a function order for the linker's -symbolorderfile instead of a preprocessed profile")
+	stats  = flag.Bool("stats", false, "print a summary of the profile instead of a preprocessed profile")
+	top    = flag.Int("top", 10, "number of hottest edges to print with -stats")
+
+	validateFlag = flag.Bool("validate", false, "check the profile for problems instead of writing a preprocessed profile")
+	bin          = flag.String("bin", "", "binary to check the profile against, with -validate")
 )
 
-func preprocess(profileFile string, outputFile string) error {
+func preprocess(profileFile string, outputFile string, order bool) error {
 	f, err := os.Open(profileFile)
 	if err != nil {
 		return fmt.Errorf("error opening profile: %w", err)
@@ -48,6 +75,9 @@ func preprocess(profileFile string, outputFile string) error {
 	if err != nil {
 		return fmt.Errorf("error parsing profile: %w", err)
 	}
+	if d.ClampedCallSiteOffsets > 0 {
+		log.Printf("warning: %d call edges with call site offsets out of range, clamped to ±%d", d.ClampedCallSiteOffsets, pgo.MaxCallSiteOffset)
+	}
 
 	var out *os.File
 	if outputFile == "" {
@@ -60,6 +90,13 @@ func preprocess(profileFile string, outputFile string) error {
 		defer out.Close()
 	}
 
+	if order {
+		if err := writeOrder(out, d); err != nil {
+			return fmt.Errorf("error writing output file: %w", err)
+		}
+		return nil
+	}
+
 	w := bufio.NewWriter(out)
 	if _, err := d.WriteTo(w); err != nil {
 		return fmt.Errorf("error writing output file: %w", err)
@@ -68,6 +105,89 @@ func preprocess(profileFile string, outputFile string) error {
 	return nil
 }
 
+// writeOrder writes the functions of d, one per line, grouped into
+// clusters by pgo.Profile.FuncOrder.
+func writeOrder(out io.Writer, d *pgo.Profile) error {
+	w := bufio.NewWriter(out)
+	var total int64
+	clusters := d.FuncOrder()
+	for _, c := range clusters {
+		total += c.Weight
+	}
+	for i, c := range clusters {
+		fmt.Fprintf(w, "# cluster %d: %d functions, weight %d", i+1, len(c.Funcs), c.Weight)
+		if total > 0 {
+			fmt.Fprintf(w, " (%.2f%%)", pgo.WeightInPercentage(c.Weight, total))
+		}
+		fmt.Fprintf(w, "\n")
+		for _, name := range c.Funcs {
+			fmt.Fprintf(w, "%s\n", name)
+		}
+	}
+	return w.Flush()
+}
+
+// printStats prints a summary of the profile in profileFile, which may be a
+// pprof profile or a preprocessed profile.
+func printStats(profileFile string, outputFile string, top int) error {
+	f, err := os.Open(profileFile)
+	if err != nil {
+		return fmt.Errorf("error opening profile: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	isSerialized, err := pgo.IsSerialized(r)
+	if err != nil {
+		return fmt.Errorf("error reading profile: %w", err)
+	}
+	var d *pgo.Profile
+	if isSerialized {
+		d, err = pgo.FromSerialized(r)
+	} else {
+		d, err = pgo.FromPProf(r)
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing profile: %w", err)
+	}
+
+	var out *os.File
+	if outputFile == "" {
+		out = os.Stdout
+	} else {
+		out, err = os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %w", err)
+		}
+		defer out.Close()
+	}
+
+	if err := writeStats(out, d.Stats(top)); err != nil {
+		return fmt.Errorf("error writing output file: %w", err)
+	}
+	return nil
+}
+
+func writeStats(out io.Writer, s *pgo.Stats) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "total weight:\t%d\n", s.TotalWeight)
+	fmt.Fprintf(w, "call edges:\t%d\n", s.Edges)
+	fmt.Fprintf(w, "functions:\t%d\n", s.Funcs)
+
+	fmt.Fprintf(w, "\ncdf\thot edges\tmin edge weight\n")
+	for _, pt := range s.CDF {
+		fmt.Fprintf(w, "%v%%\t%d\t%d\n", pt.Percent, pt.Edges, pt.MinWeight)
+	}
+
+	if len(s.Top) > 0 {
+		fmt.Fprintf(w, "\nweight\tpercent\tedge\n")
+	}
+	for _, e := range s.Top {
+		fmt.Fprintf(w, "%d\t%.2f%%\t%s:%d -> %s\n", e.Weight, pgo.WeightInPercentage(e.Weight, s.TotalWeight), e.CallerName, e.CallSiteOffset, e.CalleeName)
+	}
+	return w.Flush()
+}
+
 func main() {
 	objabi.AddVersionFlag()
 
@@ -84,7 +204,43 @@ func main() {
 		usage()
 	}
 
-	if err := preprocess(*input, *output); err != nil {
+	if *top < 0 {
+		log.Fatalf("invalid -top value %d", *top)
+	}
+	if *stats && *order {
+		log.Fatal("-stats and -order cannot be used together")
+	}
+
+	if *validateFlag {
+		out := os.Stdout
+		if *output != "" {
+			var err error
+			out, err = os.Create(*output)
+			if err != nil {
+				log.Fatalf("error creating output file: %v", err)
+			}
+		}
+		problems, err := validate(*input, *bin, out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := out.Close(); err != nil {
+			log.Fatalf("error writing output file: %v", err)
+		}
+		if problems > 0 {
+			log.Fatalf("%d problems found", problems)
+		}
+		return
+	}
+
+	if *stats {
+		if err := printStats(*input, *output, *top); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := preprocess(*input, *output, *order); err != nil {
 		log.Fatal(err)
 	}
 }
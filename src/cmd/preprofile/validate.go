@@ -0,0 +1,249 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"cmd/internal/objfile"
+	"cmd/internal/pgo"
+	"debug/dwarf"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// binFunc describes a function of a binary.
+type binFunc struct {
+	// declLine is the line of the func keyword, and maxLine the last line
+	// of the code of the function in the same file. Either is 0 if unknown,
+	// for example for functions that were only inlined.
+	declLine, maxLine int
+}
+
+// validate checks the profile in profileFile, writing each problem found to
+// out. profileFile is usually a preprocessed profile, but may also be a
+// pprof profile. If binFile is not empty, the functions and call sites of
+// the profile are also checked against the functions of that binary. It
+// returns the number of problems found.
+func validate(profileFile, binFile string, out io.Writer) (int, error) {
+	f, err := os.Open(profileFile)
+	if err != nil {
+		return 0, fmt.Errorf("error opening profile: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	isSerialized, err := pgo.IsSerialized(r)
+	if err != nil {
+		return 0, fmt.Errorf("error reading profile: %w", err)
+	}
+	var d *pgo.Profile
+	var errs []error
+	if isSerialized {
+		d, errs = pgo.ValidateSerialized(r)
+	} else {
+		d, err = pgo.FromPProf(r)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing profile: %w", err)
+		}
+	}
+
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+	problems := len(errs)
+	for _, err := range errs {
+		fmt.Fprintf(w, "%s: %v\n", profileFile, err)
+	}
+	if d.ClampedCallSiteOffsets > 0 {
+		problems++
+		fmt.Fprintf(w, "%s: %d call edges with call site offsets out of range\n", profileFile, d.ClampedCallSiteOffsets)
+	}
+
+	if binFile == "" {
+		return problems, nil
+	}
+	funcs, err := binaryFuncs(binFile)
+	if err != nil {
+		return problems, err
+	}
+
+	unknown := make(map[string]bool)
+	for _, e := range d.NamedEdgeMap.ByWeight {
+		for _, name := range []string{e.CallerName, e.CalleeName} {
+			if _, ok := funcs[genericName(name)]; !ok {
+				unknown[name] = true
+			}
+		}
+		fn, ok := funcs[genericName(e.CallerName)]
+		if !ok || fn.declLine == 0 || fn.maxLine == 0 {
+			continue
+		}
+		if n := fn.maxLine - fn.declLine; e.CallSiteOffset > n {
+			problems++
+			fmt.Fprintf(w, "%s: call site offset %d of edge %s -> %s beyond end of caller (%d lines)\n", profileFile, e.CallSiteOffset, e.CallerName, e.CalleeName, n+1)
+		}
+	}
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		problems++
+		fmt.Fprintf(w, "%s: function %s not in %s\n", profileFile, name, binFile)
+	}
+	return problems, nil
+}
+
+// genericName returns name with type arguments replaced by "[...]", which is
+// how profiles name instantiated generic functions.
+func genericName(name string) string {
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return name
+	}
+	j := strings.LastIndex(name, "]")
+	if j < i {
+		return name
+	}
+	return name[:i] + "[...]" + name[j+1:]
+}
+
+// binaryFuncs returns the functions of the binary in name, keyed by
+// genericName of their linker symbol name. The line information comes from
+// DWARF. If the binary has no DWARF, only the names of the functions in the
+// symbol table are known.
+func binaryFuncs(name string) (map[string]binFunc, error) {
+	f, err := objfile.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	funcs := make(map[string]binFunc)
+	d, err := f.DWARF()
+	if err != nil {
+		syms, err := f.Symbols()
+		if err != nil {
+			return nil, fmt.Errorf("error reading symbols of %s: %w", name, err)
+		}
+		for _, s := range syms {
+			if s.Code == 'T' || s.Code == 't' {
+				funcs[genericName(s.Name)] = binFunc{}
+			}
+		}
+		return funcs, nil
+	}
+
+	// A function is described by a subprogram entry, with its line
+	// extent taken from the line table of its compilation unit.
+	type subprogram struct {
+		name     string
+		file     *dwarf.LineFile
+		declLine int
+		ranges   [][2]uint64
+	}
+	var subs []*subprogram
+	var lr *dwarf.LineReader
+	flush := func() error {
+		if lr == nil {
+			return nil
+		}
+		type pcRange struct {
+			lo, hi uint64
+			s      *subprogram
+		}
+		var ranges []pcRange
+		for _, s := range subs {
+			for _, r := range s.ranges {
+				ranges = append(ranges, pcRange{r[0], r[1], s})
+			}
+		}
+		sort.Slice(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+		maxLine := make(map[*subprogram]int)
+		var le dwarf.LineEntry
+		for {
+			if err := lr.Next(&le); err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			i := sort.Search(len(ranges), func(i int) bool { return ranges[i].lo > le.Address }) - 1
+			if i < 0 || le.Address >= ranges[i].hi {
+				continue
+			}
+			s := ranges[i].s
+			if le.File == s.file && le.Line > maxLine[s] {
+				maxLine[s] = le.Line
+			}
+		}
+		for _, s := range subs {
+			fn := funcs[genericName(s.name)]
+			if fn.declLine == 0 {
+				fn.declLine = s.declLine
+			}
+			fn.maxLine = max(fn.maxLine, maxLine[s])
+			funcs[genericName(s.name)] = fn
+		}
+		subs, lr = nil, nil
+		return nil
+	}
+
+	dr := d.Reader()
+	for {
+		e, err := dr.Next()
+		if err != nil {
+			return nil, fmt.Errorf("error reading DWARF of %s: %w", name, err)
+		}
+		if e == nil {
+			break
+		}
+		switch e.Tag {
+		case dwarf.TagCompileUnit:
+			if err := flush(); err != nil {
+				return nil, fmt.Errorf("error reading DWARF line table of %s: %w", name, err)
+			}
+			lr, err = d.LineReader(e)
+			if err != nil {
+				return nil, fmt.Errorf("error reading DWARF line table of %s: %w", name, err)
+			}
+		case dwarf.TagSubprogram:
+			// Out-of-line copies of functions that were also
+			// inlined refer to an abstract entry for their name
+			// and declaration.
+			decl := e
+			if off, ok := e.Val(dwarf.AttrAbstractOrigin).(dwarf.Offset); ok {
+				or := d.Reader()
+				or.Seek(off)
+				if decl, err = or.Next(); err != nil || decl == nil {
+					return nil, fmt.Errorf("error reading DWARF of %s: bad abstract origin %#x", name, off)
+				}
+			}
+			s := &subprogram{}
+			s.name, _ = decl.Val(dwarf.AttrName).(string)
+			if s.name == "" {
+				break
+			}
+			if l, ok := decl.Val(dwarf.AttrDeclLine).(int64); ok {
+				s.declLine = int(l)
+			}
+			if i, ok := decl.Val(dwarf.AttrDeclFile).(int64); ok && lr != nil {
+				if files := lr.Files(); i >= 0 && int(i) < len(files) {
+					s.file = files[i]
+				}
+			}
+			if ranges, err := d.Ranges(e); err == nil {
+				s.ranges = ranges
+			}
+			subs = append(subs, s)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, fmt.Errorf("error reading DWARF line table of %s: %w", name, err)
+	}
+	return funcs, nil
+}
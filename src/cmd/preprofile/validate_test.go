@@ -0,0 +1,114 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validateSrc = `package main
+
+//go:noinline
+func f(x int) int {
+	return x + 1
+}
+
+//go:noinline
+func g[T any](x T) T {
+	return x
+}
+
+func main() {
+	println(f(1))
+	println(g(2))
+}
+`
+
+// buildValidateBinary builds validateSrc and returns the path of the binary.
+func buildValidateBinary(t *testing.T) string {
+	testenv.MustHaveGoBuild(t)
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "x.go")
+	if err := os.WriteFile(src, []byte(validateSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(dir, "x.exe")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-o", exe, src)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	return exe
+}
+
+func TestValidate(t *testing.T) {
+	exe := buildValidateBinary(t)
+
+	for _, tc := range []struct {
+		name string
+		prof string
+		want []string // expected problems, one per line
+	}{
+		{
+			name: "match",
+			prof: "main.main\nmain.f\n1 100\n",
+		},
+		{
+			name: "generic",
+			prof: "main.main\nmain.g[...]\n2 100\n",
+		},
+		{
+			name: "mismatch",
+			prof: "main.main\nmain.f\n1 100\nmain.main\nmain.renamed\n1 10\n",
+			want: []string{"function main.renamed not in"},
+		},
+		{
+			name: "offset",
+			prof: "main.main\nmain.f\n100 100\n",
+			want: []string{"call site offset 100 of edge main.main -> main.f beyond end of caller"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			prof := filepath.Join(t.TempDir(), "x.pgo")
+			if err := os.WriteFile(prof, []byte("GO PREPROFILE V1\n"+tc.prof), 0644); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			problems, err := validate(prof, exe, &out)
+			if err != nil {
+				t.Fatalf("validate got err %v want nil", err)
+			}
+			if problems != len(tc.want) {
+				t.Errorf("validate got %d problems want %d, output:\n%s", problems, len(tc.want), out.String())
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("validate output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGenericName(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"main.f", "main.f"},
+		{"main.g[go.shape.int]", "main.g[...]"},
+		{"main.g[...]", "main.g[...]"},
+		{"example.com/p.(*T[go.shape.string,go.shape.int]).M", "example.com/p.(*T[...]).M"},
+		{"main.g[", "main.g["},
+	} {
+		if got := genericName(tc.in); got != tc.want {
+			t.Errorf("genericName(%q) got %q want %q", tc.in, got, tc.want)
+		}
+	}
+}
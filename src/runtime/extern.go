@@ -117,6 +117,14 @@ It is a comma-separated list of name=val pairs setting these named variables:
 	but is helpful in debugging scavenger-related issues on other platforms. Currently,
 	only supported on Linux.
 
+	hugepagetext: setting hugepagetext=1 causes the runtime to advise the OS, at startup,
+	to back the profile-hot text of the program with transparent huge pages. The text
+	is not copied or remapped, so this only has an effect if the OS supports huge pages
+	for file-backed executable mappings (on Linux, CONFIG_READ_ONLY_THP_FOR_FS). The hot text is only laid out for huge pages
+	when the program is linked with -ldflags='-hotcoldtext -hottextalign=2097152' and
+	compiled with a PGO profile and -gcflags=-d=pgotextlayout=1. Currently, only
+	supported on Linux.
+
 	inittrace: setting inittrace=1 causes the runtime to emit a single line to standard
 	error for each package with init work, summarizing the execution time and memory
 	allocation. No information is printed for inits executed as part of plugin loading
@@ -0,0 +1,28 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import "unsafe"
+
+// hugePageText advises the OS that the profile-hot text of each module
+// should be backed by huge pages. The linker groups the hot text and, with
+// -hottextalign, aligns it to huge page boundaries (see
+// cmd/link/internal/ld/textlayout.go). Only the huge pages fully inside the
+// hot text are affected.
+//
+// This is only an madvise(MADV_HUGEPAGE) hint on the file-backed text
+// mapping; the text is not copied into anonymous huge pages. On Linux the
+// hint only has an effect on kernels built with CONFIG_READ_ONLY_THP_FOR_FS,
+// and is otherwise ignored. It is enabled with GODEBUG=hugepagetext=1.
+func hugePageText() {
+	if debug.hugepagetext == 0 {
+		return
+	}
+	for md := &firstmoduledata; md != nil; md = md.next {
+		if md.hottext < md.ehottext {
+			sysHugePage(unsafe.Pointer(md.hottext), md.ehottext-md.hottext)
+		}
+	}
+}
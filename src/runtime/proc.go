@@ -835,6 +835,7 @@ func schedinit() {
 	checkfds()
 	parsedebugvars()
 	gcinit()
+	hugePageText()
 
 	// Allocate stack space that can be used when crashing due to bad stack
 	// conditions, e.g. morestack on g0.
@@ -326,6 +326,7 @@ var debug struct {
 	tracebackancestors       int32
 	asyncpreemptoff          int32
 	harddecommit             int32
+	hugepagetext             int32
 	adaptivestackstart       int32
 	tracefpunwindoff         int32
 	traceadvanceperiod       int32
@@ -376,6 +377,7 @@ var dbgvars = []*dbgVar{
 	{name: "gcstoptheworld", value: &debug.gcstoptheworld},
 	{name: "gctrace", value: &debug.gctrace},
 	{name: "harddecommit", value: &debug.harddecommit},
+	{name: "hugepagetext", value: &debug.hugepagetext},
 	{name: "inittrace", value: &debug.inittrace},
 	{name: "invalidptr", value: &debug.invalidptr},
 	{name: "madvdontneed", value: &debug.madvdontneed},
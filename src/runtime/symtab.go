@@ -399,6 +399,7 @@ type moduledata struct {
 	minpc, maxpc uintptr
 
 	text, etext           uintptr
+	hottext, ehottext     uintptr // profile-hot text; 0 if not laid out separately
 	noptrdata, enoptrdata uintptr
 	data, edata           uintptr
 	bss, ebss             uintptr